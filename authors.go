@@ -0,0 +1,132 @@
+package go_scholar
+
+import (
+	"context"
+	"errors"
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Author is a result of an author search. UserID can be passed to QueryProfile
+type Author struct {
+	UserID       string
+	Name         string
+	Affiliation  string
+	EmailDomain  string // domain of the verified email address, e.g. "uoguelph.ca"
+	NumCitations int
+	Interests    []string
+}
+
+// SearchAuthors returns up to limit authors whose profile matches name, or a single page of them if limit <= 0
+func (sch *Scholar) SearchAuthors(name string, limit int) ([]*Author, error) {
+	return sch.SearchAuthorsContext(context.Background(), name, limit)
+}
+
+// SearchAuthorsContext is SearchAuthors with a context which can cancel the requests and the waits between them
+func (sch *Scholar) SearchAuthorsContext(ctx context.Context, name string, limit int) ([]*Author, error) {
+	return sch.queryAuthors(ctx, name, limit)
+}
+
+// AuthorsByLabel returns up to limit authors who list label as one of their interests. The label may be given
+// as shown on a profile (e.g. "Wireless Mesh Networks") or as used in URLs (e.g. "wireless_mesh_networks")
+func (sch *Scholar) AuthorsByLabel(label string, limit int) ([]*Author, error) {
+	return sch.AuthorsByLabelContext(context.Background(), label, limit)
+}
+
+// AuthorsByLabelContext is AuthorsByLabel with a context which can cancel the requests and the waits between them
+func (sch *Scholar) AuthorsByLabelContext(ctx context.Context, label string, limit int) ([]*Author, error) {
+	id := labelID(label)
+	if id == "" {
+		return nil, errors.New("Scholar: empty author label")
+	}
+	return sch.queryAuthors(ctx, "label:"+id, limit)
+}
+
+// labelID converts an interest label to the form used in URLs
+func labelID(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), "_"))
+}
+
+// number of authors Google Scholar shows on each page of an author search
+const authorsPageSize = 10
+
+// queryAuthors pages through the author search results for mauthors, following the after_author token of
+// each page to the next one
+func (sch *Scholar) queryAuthors(ctx context.Context, mauthors string, limit int) ([]*Author, error) {
+	if strings.TrimSpace(mauthors) == "" {
+		return nil, errors.New("Scholar: empty author search")
+	}
+	limit = pageLimit(limit, authorsPageSize)
+
+	var authors []*Author
+	afterAuthor := ""
+	astart := 0
+	for len(authors) < limit {
+		pageAuthors, next, err := sch.fetchAuthorsPage(ctx, mauthors, afterAuthor, astart)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, pageAuthors...)
+
+		// If there is no next page, we've reached the end
+		if len(pageAuthors) == 0 || next == "" {
+			break
+		}
+		afterAuthor = next
+		astart += len(pageAuthors)
+	}
+
+	if len(authors) > limit {
+		authors = authors[:limit]
+	}
+	return authors, nil
+}
+
+// fetchAuthorsPage fetches a single page of author search results, returning the authors along with the
+// after_author token of the next page (empty on the last page)
+func (sch *Scholar) fetchAuthorsPage(ctx context.Context, mauthors string, afterAuthor string, astart int) ([]*Author, string, error) {
+	params := url.Values{}
+	params.Set("view_op", "search_authors")
+	params.Set("mauthors", mauthors)
+	params.Set("hl", "en")
+	if afterAuthor != "" {
+		params.Set("after_author", afterAuthor)
+		params.Set("astart", strconv.Itoa(astart))
+	}
+
+	requestURL := sch.baseURL + "/citations?" + params.Encode()
+	doc, err := sch.fetchDocument(ctx, requestURL, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var authors []*Author
+	doc.Find(".gsc_1usr").Each(func(i int, s *goquery.Selection) {
+		link := s.Find(".gs_ai_name a")
+		author := &Author{
+			Name:         normalizeSpace(link.Text()),
+			Affiliation:  normalizeSpace(s.Find(".gs_ai_aff").Text()),
+			NumCitations: parseLeadingInt(strings.TrimPrefix(normalizeSpace(s.Find(".gs_ai_cby").Text()), "Cited by")),
+		}
+		href, _ := link.Attr("href")
+		author.UserID = linkParam(href, "user")
+		author.EmailDomain = strings.TrimPrefix(normalizeSpace(s.Find(".gs_ai_eml").Text()), "Verified email at ")
+		s.Find(".gs_ai_int .gs_ai_one_int").Each(func(i int, l *goquery.Selection) {
+			author.Interests = append(author.Interests, normalizeSpace(l.Text()))
+		})
+		authors = append(authors, author)
+	})
+
+	// the next button links to the next page with javascript, e.g.
+	// window.location='/citations?view_op\x3dsearch_authors\x26...\x26after_author\x3d8SkmAAAA8P8J\x26astart\x3d10'
+	onclick, _ := doc.Find(".gsc_pgn_pnx").Attr("onclick")
+	onclick = strings.NewReplacer(`\x3d`, "=", `\x26`, "&").Replace(onclick)
+	next := ""
+	if _, nextURL, found := strings.Cut(onclick, "'"); found {
+		nextURL, _, _ = strings.Cut(nextURL, "'")
+		next = linkParam(nextURL, "after_author")
+	}
+	return authors, next, nil
+}
@@ -0,0 +1,80 @@
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSearchAuthors(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	mockClient := &MockRecordingHTTPClient{}
+	sch.SetHTTPClient(mockClient)
+
+	authors, err := sch.SearchAuthors("Jason Ernst", 3)
+	assert.NoError(t, err)
+	assert.Len(t, authors, 3)
+	assert.Len(t, mockClient.urls, 1)
+	requestURL, err := url.Parse(mockClient.urls[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "Jason Ernst", requestURL.Query().Get("mauthors"))
+
+	assert.Equal(t, &Author{
+		UserID:       "SbUmSEAAAAAJ",
+		Name:         "Jason Ernst",
+		Affiliation:  "University of Guelph",
+		EmailDomain:  "uoguelph.ca",
+		NumCitations: 1083,
+		Interests:    []string{"Wireless Mesh Networks", "Decentralization", "Robotics"},
+	}, authors[0])
+	assert.Equal(t, "", authors[2].EmailDomain)
+	assert.Equal(t, 0, authors[2].NumCitations)
+}
+
+func TestAuthorsByLabelPagination(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	mockClient := &MockRecordingHTTPClient{}
+	sch.SetHTTPClient(mockClient)
+
+	// the sample page has 3 authors and a next page, so 5 authors take 2 pages
+	authors, err := sch.AuthorsByLabel("Wireless Mesh Networks", 5)
+	assert.NoError(t, err)
+	assert.Len(t, authors, 5)
+	assert.Len(t, mockClient.urls, 2)
+
+	first, _ := url.Parse(mockClient.urls[0])
+	assert.Equal(t, "label:wireless_mesh_networks", first.Query().Get("mauthors"))
+	assert.Equal(t, "", first.Query().Get("after_author"))
+	second, _ := url.Parse(mockClient.urls[1])
+	assert.Equal(t, "8SkmAAAA8P8J", second.Query().Get("after_author"))
+	assert.Equal(t, "3", second.Query().Get("astart"))
+}
+
+func TestAuthorsEmptyQuery(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	mockClient := &MockRecordingHTTPClient{}
+	sch.SetHTTPClient(mockClient)
+
+	for _, label := range []string{"", "   "} {
+		_, err := sch.AuthorsByLabel(label, 5)
+		assert.Error(t, err)
+	}
+	_, err := sch.SearchAuthors(" ", 5)
+	assert.Error(t, err)
+	assert.Empty(t, mockClient.urls)
+}
+
+// Test that a limit <= 0 returns a page of results, like the other result listings
+func TestAuthorsDefaultLimit(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockRecordingHTTPClient{})
+
+	authors, err := sch.SearchAuthors("Jason Ernst", 0)
+	assert.NoError(t, err)
+	assert.Len(t, authors, authorsPageSize)
+}
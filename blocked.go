@@ -0,0 +1,56 @@
+package go_scholar
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// markers of the CAPTCHA pages served by Google Scholar itself
+var captchaMarkers = [][]byte{
+	[]byte(`id="gs_captcha_ccl"`),
+	[]byte(`id="gs_captcha_f"`),
+	[]byte(`class="g-recaptcha"`),
+	[]byte("Please show you're not a robot"),
+}
+
+// markers of the unusual traffic pages served by google.com/sorry
+var unusualTrafficMarkers = [][]byte{
+	[]byte("unusual traffic from your computer network"),
+	[]byte("Our systems have detected unusual traffic"),
+}
+
+// detectBlockPage returns a *BlockedError if the response is a block page rather than the requested page:
+// a redirect (followed or not) to google.com/sorry or to the consent page, or a page with a CAPTCHA or an
+// unusual traffic notice
+func detectBlockPage(resp *http.Response, body []byte, requestURL string) error {
+	finalURL := ""
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+	}
+	for _, location := range []string{finalURL, resp.Header.Get("Location")} {
+		parsed, err := url.Parse(location)
+		if err != nil || location == "" {
+			continue
+		}
+		if strings.HasPrefix(parsed.Host, "consent.") {
+			return &BlockedError{URL: requestURL, Reason: "consent"}
+		}
+		if strings.HasPrefix(parsed.Path, "/sorry/") {
+			return &BlockedError{URL: requestURL, Reason: "unusual traffic"}
+		}
+	}
+
+	for _, marker := range captchaMarkers {
+		if bytes.Contains(body, marker) {
+			return &BlockedError{URL: requestURL, Reason: "captcha"}
+		}
+	}
+	for _, marker := range unusualTrafficMarkers {
+		if bytes.Contains(body, marker) {
+			return &BlockedError{URL: requestURL, Reason: "unusual traffic"}
+		}
+	}
+	return nil
+}
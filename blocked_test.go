@@ -0,0 +1,76 @@
+package go_scholar
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleCaptchaPage = `<html><body><div id="gs_captcha_ccl"><h1>Please show you're not a robot</h1>
+<form id="gs_captcha_f" method="post"><div class="g-recaptcha" data-sitekey="x"></div></form></div></body></html>`
+
+const sampleUnusualTrafficPage = `<html><body><div>Our systems have detected unusual traffic from your computer
+network. This page checks to see if it's really you sending the requests, and not a robot.</div></body></html>`
+
+// MockRedirectHTTPClient answers every request as if it was redirected to location
+type MockRedirectHTTPClient struct {
+	location string
+}
+
+func (m *MockRedirectHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	redirected, _ := http.NewRequest("GET", m.location, nil)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader("<html><body>Before you continue to Google</body></html>")),
+		Request:    redirected,
+	}, nil
+}
+
+func TestBlockPageDetection(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+
+	testCases := map[string]HTTPClient{
+		"captcha":         &MockStatusHTTPClient{statusCode: 200, body: sampleCaptchaPage},
+		"unusual traffic": &MockStatusHTTPClient{statusCode: 200, body: sampleUnusualTrafficPage},
+		"consent":         &MockRedirectHTTPClient{location: "https://consent.google.com/ml?continue=https://scholar.google.com/citations"},
+	}
+	for reason, client := range testCases {
+		t.Run(reason, func(t *testing.T) {
+			sch.SetHTTPClient(client)
+			_, err := sch.QueryProfile("SbUmSEAAAAAJ", 1)
+			assert.ErrorIs(t, err, ErrBlocked)
+			var blockedErr *BlockedError
+			assert.True(t, errors.As(err, &blockedErr))
+			assert.Equal(t, reason, blockedErr.Reason)
+		})
+	}
+}
+
+// Test that a CAPTCHA page doesn't overwrite the cached profile with an empty one
+func TestBlockPageKeepsCache(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	articles, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 5)
+	assert.NoError(t, err)
+	assert.Len(t, articles, 5)
+
+	profile, _, _ := sch.store.LoadProfile("SbUmSEAAAAAJ")
+	profile.LastRetrieved = time.Now().Add(-8 * 24 * time.Hour)
+	sch.store.StoreProfile(profile)
+
+	sch.SetHTTPClient(&MockStatusHTTPClient{statusCode: 200, body: sampleCaptchaPage})
+	articles, err = sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 5)
+	assert.NoError(t, err)
+	assert.Len(t, articles, 5)
+
+	profile, _, _ = sch.store.LoadProfile("SbUmSEAAAAAJ")
+	assert.Len(t, profile.Articles, 5)
+	assert.Equal(t, "Jason Ernst", profile.Name)
+}
@@ -0,0 +1,240 @@
+package go_scholar
+
+import (
+	"encoding/json"
+	"fmt"
+	bolt "go.etcd.io/bbolt"
+	"strconv"
+	"time"
+)
+
+var (
+	profileBucket = []byte("profiles")
+	articleBucket = []byte("articles")
+	listingBucket = []byte("listings")
+	metaBucket    = []byte("meta") // schema and library versions of the database
+)
+
+// BoltStore is a Store kept in a single bbolt database file. Every profile, article and listing is written to
+// disk as soon as it is stored, in its own transaction, so a crawl which is interrupted keeps everything
+// fetched before the interruption. Nothing is loaded into memory up front, values are read from the file on demand
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens (creating it if needed) the database file at path. The file is locked while it is
+// open, so it can't be shared by two processes at once. Close must be called when done with the store
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("Scholar: opening cache database %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{profileBucket, articleBucket, listingBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return migrateBolt(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Scholar: opening cache database %s: %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// migrateBolt upgrades the profiles and articles of a database written with an older schema version (see
+// cacheMigrations), and records the current versions. A database without versions is a new one
+func migrateBolt(tx *bolt.Tx) error {
+	meta := tx.Bucket(metaBucket)
+	version := cacheSchemaVersion
+	if data := meta.Get([]byte("SchemaVersion")); data != nil {
+		if err := json.Unmarshal(data, &version); err != nil {
+			return err
+		}
+	}
+	if version > cacheSchemaVersion {
+		return fmt.Errorf("%w: database has schema version %d (written by version %s), this version supports up to %d",
+			ErrCacheVersion, version, meta.Get([]byte("LibraryVersion")), cacheSchemaVersion)
+	}
+	if version < cacheSchemaVersion {
+		if err := migrateBoltBucket(tx, "profiles", profileBucket, version); err != nil {
+			return err
+		}
+		if err := migrateBoltBucket(tx, "articles", articleBucket, version); err != nil {
+			return err
+		}
+		if err := migrateBoltBucket(tx, "listings", listingBucket, version); err != nil {
+			return err
+		}
+	}
+	if err := meta.Put([]byte("SchemaVersion"), []byte(strconv.Itoa(cacheSchemaVersion))); err != nil {
+		return err
+	}
+	return meta.Put([]byte("LibraryVersion"), []byte(Version))
+}
+
+// migrateBoltBucket runs the migrations from version on the values of bucket. The migrations work on the
+// map of a whole cache file, so the bucket is migrated as one
+func migrateBoltBucket(tx *bolt.Tx, kind string, bucket []byte, version int) error {
+	values := map[string]json.RawMessage{}
+	err := tx.Bucket(bucket).ForEach(func(key, data []byte) error {
+		values[string(key)] = append(json.RawMessage(nil), data...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	for from := version; from < cacheSchemaVersion; from++ {
+		data, err = cacheMigrations[from](kind, data)
+		if err != nil {
+			return fmt.Errorf("migrating %s from schema version %d: %w", kind, from, err)
+		}
+	}
+	values = map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if err := tx.DeleteBucket(bucket); err != nil {
+		return err
+	}
+	migrated, err := tx.CreateBucket(bucket)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		if err := migrated.Put([]byte(key), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the database file
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltStore) LoadProfile(user string) (Profile, bool, error) {
+	var profile Profile
+	ok, err := b.load(profileBucket, user, &profile)
+	return profile, ok, err
+}
+
+func (b *BoltStore) StoreProfile(profile Profile) error {
+	return b.store(profileBucket, profile.User, profile)
+}
+
+func (b *BoltStore) DeleteProfile(user string) error {
+	return b.delete(profileBucket, user)
+}
+
+func (b *BoltStore) ListProfiles() ([]CacheEntry, error) {
+	return b.list(profileBucket)
+}
+
+func (b *BoltStore) LoadArticle(articleURL string) (*Article, bool, error) {
+	article := &Article{}
+	ok, err := b.load(articleBucket, articleURL, article)
+	if !ok {
+		return nil, false, err
+	}
+	return article, true, nil
+}
+
+func (b *BoltStore) StoreArticle(article *Article) error {
+	return b.store(articleBucket, article.ScholarURL, article)
+}
+
+func (b *BoltStore) DeleteArticle(articleURL string) error {
+	return b.delete(articleBucket, articleURL)
+}
+
+func (b *BoltStore) ListArticles() ([]CacheEntry, error) {
+	return b.list(articleBucket)
+}
+
+func (b *BoltStore) LoadListing(key string) (Listing, bool, error) {
+	var listing Listing
+	ok, err := b.load(listingBucket, key, &listing)
+	return listing, ok, err
+}
+
+func (b *BoltStore) StoreListing(listing Listing) error {
+	return b.store(listingBucket, listing.Key, listing)
+}
+
+func (b *BoltStore) DeleteListing(key string) error {
+	return b.delete(listingBucket, key)
+}
+
+func (b *BoltStore) ListListings() ([]CacheEntry, error) {
+	return b.list(listingBucket)
+}
+
+// load decodes the value of key in bucket into value, returning false if there is none
+func (b *BoltStore) load(bucket []byte, key string, value interface{}) (bool, error) {
+	var ok bool
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(data, value)
+	})
+	if err != nil {
+		return false, fmt.Errorf("Scholar: loading %s %s: %w", bucket, key, err)
+	}
+	return ok, nil
+}
+
+// store writes value as the value of key in bucket
+func (b *BoltStore) store(bucket []byte, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = b.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(bucket).Put([]byte(key), data)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("Scholar: storing %s %s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// delete removes key from bucket
+func (b *BoltStore) delete(bucket []byte, key string) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("Scholar: deleting %s %s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// list returns the keys of bucket along with the LastRetrieved timestamps of their values
+func (b *BoltStore) list(bucket []byte) ([]CacheEntry, error) {
+	var entries []CacheEntry
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(key, data []byte) error {
+			// only the timestamp is decoded, the other fields of the value are skipped
+			var value struct{ LastRetrieved time.Time }
+			if err := json.Unmarshal(data, &value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			entries = append(entries, CacheEntry{Key: string(key), LastRetrieved: value.LastRetrieved})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Scholar: listing %s: %w", bucket, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,98 @@
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that the bolt store persists what is fetched without saving, and can be reopened
+func TestBoltStore(t *testing.T) {
+	path := t.TempDir() + "/cache.db"
+	store, err := OpenBoltStore(path)
+	assert.NoError(t, err)
+
+	sch := NewWithStore(store)
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+	articles, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 2)
+	assert.NoError(t, err)
+	assert.Len(t, articles, 2)
+	assert.NoError(t, store.Close())
+
+	reopened, err := OpenBoltStore(path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+
+	profile, ok, err := reopened.LoadProfile("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Jason Ernst", profile.Name)
+	assert.Len(t, profile.Articles, 2)
+
+	// the articles are stored relative to the host
+	articleURL := strings.TrimPrefix(articles[0].ScholarURL, BaseURL)
+	assert.Equal(t, articleURL, profile.Articles[0])
+	article, ok, err := reopened.LoadArticle(articleURL)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, articles[0].Title, article.Title)
+	assert.True(t, articles[0].LastRetrieved.Equal(article.LastRetrieved))
+
+	profiles, err := reopened.ListProfiles()
+	assert.NoError(t, err)
+	assert.Len(t, profiles, 1)
+	assert.Equal(t, "SbUmSEAAAAAJ", profiles[0].Key)
+	assert.True(t, profile.LastRetrieved.Equal(profiles[0].LastRetrieved))
+
+	assert.NoError(t, reopened.DeleteArticle(articleURL))
+	_, ok, err = reopened.LoadArticle(articleURL)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// the cached profile is served from the database without any requests
+	sch = NewWithStore(reopened)
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockAlwaysFailHTTPClient{})
+	info, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Jason Ernst", info.Name)
+}
+
+// Test that JSON caches can be imported into the bolt store with LoadCache
+func TestBoltStoreLoadCache(t *testing.T) {
+	dir := t.TempDir()
+	sch := New(dir+"/profiles.json", dir+"/articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+	_, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+	assert.NoError(t, sch.SaveCache(dir+"/profiles.json", dir+"/articles.json"))
+
+	store, err := OpenBoltStore(dir + "/cache.db")
+	assert.NoError(t, err)
+	defer store.Close()
+	assert.NoError(t, NewWithStore(store).LoadCache(dir+"/profiles.json", dir+"/articles.json"))
+
+	profile, ok, err := store.LoadProfile("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, profile.Articles, 1)
+}
+
+// Test that a database from a newer schema isn't opened
+func TestBoltStoreNewerVersion(t *testing.T) {
+	path := t.TempDir() + "/cache.db"
+	store, err := OpenBoltStore(path)
+	assert.NoError(t, err)
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Put([]byte("SchemaVersion"), []byte("99"))
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Close())
+
+	_, err = OpenBoltStore(path)
+	assert.ErrorIs(t, err, ErrCacheVersion)
+}
@@ -0,0 +1,389 @@
+package go_scholar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The JSON cache files are written to a temporary file which is renamed over the previous file, so a crash
+// mid-write never leaves a truncated cache. The previous generation of each file is kept next to it with
+// backupSuffix, and is loaded when the current file can't be. Loads and saves hold an advisory lock on the
+// profile cache path with lockSuffix, so processes sharing the same cache files don't clobber each other
+const backupSuffix = ".bak"
+const lockSuffix = ".lock"
+
+// a cache file which can't be decoded is moved aside with corruptSuffix rather than overwritten
+const corruptSuffix = ".corrupt"
+
+// cacheSchemaVersion is the version of the format of the profiles, articles and listings in the cache files.
+// It must be increased, with a migration added to cacheMigrations, whenever a change to Profile, Article or
+// Listing would make older cache files decode incorrectly
+const cacheSchemaVersion = 2
+
+// cacheEnvelope is the format of the cache files, the map of profiles, articles or listings is the Data
+type cacheEnvelope struct {
+	SchemaVersion  int
+	LibraryVersion string    // Version of the library which wrote the file
+	CreatedAt      time.Time // when the file was written
+	Data           json.RawMessage
+}
+
+// cacheMigrations[v] upgrades the Data of a cache file from schema version v to v+1. kind is "profiles",
+// "articles" or "listings" (listing files were first written with version 2)
+var cacheMigrations = []func(kind string, data json.RawMessage) (json.RawMessage, error){
+	// version 0 is the bare map written before the envelope, which is also the Data of version 1
+	func(kind string, data json.RawMessage) (json.RawMessage, error) {
+		return data, nil
+	},
+	// version 2 stores the links to Google Scholar relative to the host
+	migrateRelativeURLs,
+}
+
+// migrateRelativeURLs makes the links to Google Scholar of the profiles or articles in data relative to the
+// host, including the keys of the articles
+func migrateRelativeURLs(kind string, data json.RawMessage) (json.RawMessage, error) {
+	var values map[string]map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // numbers are re-encoded as they are
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	relative := func(value interface{}) interface{} {
+		switch link := value.(type) {
+		case string:
+			return relativeScholarURL(link, "")
+		case []interface{}:
+			for i := range link {
+				if s, ok := link[i].(string); ok {
+					link[i] = relativeScholarURL(s, "")
+				}
+			}
+		}
+		return value
+	}
+	fields := []string{"PhotoURL", "Articles"}
+	if kind == "articles" {
+		fields = []string{"ScholarURL", "ScholarCitedByURLs", "ScholarVersionsURLs", "ScholarRelatedURLs"}
+	}
+	migrated := make(map[string]map[string]interface{}, len(values))
+	for key, value := range values {
+		if value != nil {
+			for _, field := range fields {
+				if fieldValue, ok := value[field]; ok {
+					value[field] = relative(fieldValue)
+				}
+			}
+		}
+		if kind == "articles" {
+			key = relativeScholarURL(key, "")
+		}
+		migrated[key] = value
+	}
+	return json.Marshal(migrated)
+}
+
+// listingCachePath returns the path of the listing cache which goes with the article cache at articleCache,
+// e.g. articles.listings.json for articles.json
+func listingCachePath(articleCache string) string {
+	ext := filepath.Ext(articleCache)
+	return strings.TrimSuffix(articleCache, ext) + ".listings" + ext
+}
+
+// loadCacheFiles decodes the JSON cache files into store. Nothing is loaded unless the profile and article
+// files can be decoded. The listing file is optional, since caches saved by older versions don't have one
+func loadCacheFiles(store Store, profileCache string, articleCache string, listingCache string, logger *slog.Logger) error {
+	// there is nothing to lock before the cache is first saved
+	if _, err := os.Stat(profileCache); err == nil {
+		unlock, err := lockFile(profileCache+lockSuffix, false)
+		if err == nil {
+			defer unlock()
+		} else {
+			// the cache can still be read from a directory we can't create the lock file in
+			logger.Warn("Loading cache without lock", "path", profileCache, "error", err)
+		}
+	}
+
+	var regularProfileMap map[string]Profile
+	err := decodeCacheFileOrBackup(profileCache, "profiles", &regularProfileMap, logger)
+	if err != nil {
+		return fmt.Errorf("Scholar: loading profile cache: %w", err)
+	}
+	var regularArticleMap map[string]*Article
+	err = decodeCacheFileOrBackup(articleCache, "articles", &regularArticleMap, logger)
+	if err != nil {
+		return fmt.Errorf("Scholar: loading article cache: %w", err)
+	}
+	var regularListingMap map[string]Listing
+	err = decodeCacheFileOrBackup(listingCache, "listings", &regularListingMap, logger)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Scholar: loading listing cache: %w", err)
+	}
+
+	for key, value := range regularProfileMap {
+		value.User = key
+		if err := store.StoreProfile(value); err != nil {
+			return fmt.Errorf("Scholar: loading profile cache: %w", err)
+		}
+	}
+	logger.Info("Loaded profile cache", "path", profileCache, "profiles", len(regularProfileMap))
+	for key, value := range regularArticleMap {
+		value.ScholarURL = key
+		if err := store.StoreArticle(value); err != nil {
+			return fmt.Errorf("Scholar: loading article cache: %w", err)
+		}
+	}
+	logger.Info("Loaded article cache", "path", articleCache, "articles", len(regularArticleMap))
+	for key, value := range regularListingMap {
+		value.Key = key
+		if err := store.StoreListing(value); err != nil {
+			return fmt.Errorf("Scholar: loading listing cache: %w", err)
+		}
+	}
+	logger.Info("Loaded listing cache", "path", listingCache, "listings", len(regularListingMap))
+	return nil
+}
+
+// deletionTracker is implemented by the stores which remember the values deleted from them (see
+// MemoryStore.deletedVersion)
+type deletionTracker interface {
+	deletedVersion(kind string, key string) (time.Time, bool)
+}
+
+// saveCacheFiles writes the contents of store to the JSON cache files. Entries which another process saved
+// to the files since they were loaded are kept, unless store has a more recently retrieved version of them,
+// or store has deleted them and the files don't have a more recently retrieved version than the deleted one.
+// Each file is still written when another can't be
+func saveCacheFiles(store Store, profileCache string, articleCache string, listingCache string, logger *slog.Logger) error {
+	unlock, err := lockFile(profileCache+lockSuffix, true)
+	if err != nil {
+		return fmt.Errorf("Scholar: locking cache: %w", err)
+	}
+	defer unlock()
+
+	deleted := func(kind string) func(key string) (time.Time, bool) {
+		return func(key string) (time.Time, bool) {
+			if tracker, ok := store.(deletionTracker); ok {
+				return tracker.deletedVersion(kind, key)
+			}
+			return time.Time{}, false
+		}
+	}
+
+	regularProfileMap, profileErr := storeMap(store.ListProfiles, store.LoadProfile)
+	if profileErr == nil {
+		profileErr = saveCacheFile(profileCache, "profiles", regularProfileMap,
+			func(profile Profile) time.Time { return profile.LastRetrieved }, deleted("profiles"), logger)
+	}
+	if profileErr != nil {
+		profileErr = fmt.Errorf("Scholar: saving profile cache: %w", profileErr)
+	}
+
+	regularArticleMap, articleErr := storeMap(store.ListArticles, store.LoadArticle)
+	if articleErr == nil {
+		articleErr = saveCacheFile(articleCache, "articles", regularArticleMap,
+			func(article *Article) time.Time { return article.LastRetrieved }, deleted("articles"), logger)
+	}
+	if articleErr != nil {
+		articleErr = fmt.Errorf("Scholar: saving article cache: %w", articleErr)
+	}
+
+	regularListingMap, listingErr := storeMap(store.ListListings, store.LoadListing)
+	if listingErr == nil {
+		listingErr = saveCacheFile(listingCache, "listings", regularListingMap,
+			func(listing Listing) time.Time { return listing.LastRetrieved }, deleted("listings"), logger)
+	}
+	if listingErr != nil {
+		listingErr = fmt.Errorf("Scholar: saving listing cache: %w", listingErr)
+	}
+	return errors.Join(profileErr, articleErr, listingErr)
+}
+
+// saveCacheFile writes values to the JSON cache file at path. The entries another process saved to the file
+// are merged into values first, unless values has a more recently retrieved version of them or they were
+// deleted (deleted returns the LastRetrieved of the deleted version) and aren't more recent than that
+func saveCacheFile[V any](path string, kind string, values map[string]V, lastRetrieved func(V) time.Time,
+	deleted func(key string) (time.Time, bool), logger *slog.Logger) error {
+	var savedMap map[string]V
+	err := decodeCacheFile(path, kind, &savedMap)
+	for key, saved := range savedMap {
+		if value, ok := values[key]; ok {
+			if lastRetrieved(saved).After(lastRetrieved(value)) {
+				values[key] = saved
+			}
+		} else if deletedRetrieved, ok := deleted(key); !ok || lastRetrieved(saved).After(deletedRetrieved) {
+			values[key] = saved
+		}
+	}
+	// a file written by a newer version of the library isn't overwritten
+	if errors.Is(err, ErrCacheVersion) {
+		return err
+	}
+	return writeCacheFile(path, kind, values, logger)
+}
+
+// storeMap returns all the values of a kind in a store by key, given the List and Load methods of that kind
+func storeMap[V any](list func() ([]CacheEntry, error), load func(key string) (V, bool, error)) (map[string]V, error) {
+	entries, err := list()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]V, len(entries))
+	for _, entry := range entries {
+		value, ok, err := load(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			values[entry.Key] = value
+		}
+	}
+	return values, nil
+}
+
+// decodeCacheFileOrBackup decodes the JSON cache file at path into value, falling back to its backup if
+// the file is missing or corrupt
+func decodeCacheFileOrBackup(path string, kind string, value interface{}, logger *slog.Logger) error {
+	err := decodeCacheFile(path, kind, value)
+	if err == nil || errors.Is(err, ErrCacheVersion) {
+		return err
+	}
+	if backupErr := decodeCacheFile(path+backupSuffix, kind, value); backupErr == nil {
+		logger.Warn("Loaded cache backup", "path", path+backupSuffix, "error", err)
+		return nil
+	}
+	return err
+}
+
+// decodeCacheFile decodes the JSON cache file at path into value, migrating it from older schema versions.
+// Files without an envelope are the bare maps of schema version 0
+func decodeCacheFile(path string, kind string, value interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(content, &fields)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	envelope := cacheEnvelope{Data: content}
+	if _, ok := fields["SchemaVersion"]; ok {
+		if err := json.Unmarshal(content, &envelope); err != nil {
+			return fmt.Errorf("decoding %s: %w", path, err)
+		}
+	}
+	if envelope.SchemaVersion > cacheSchemaVersion {
+		return fmt.Errorf("%w: %s has schema version %d (written by version %s), this version supports up to %d",
+			ErrCacheVersion, path, envelope.SchemaVersion, envelope.LibraryVersion, cacheSchemaVersion)
+	}
+	data := envelope.Data
+	for version := envelope.SchemaVersion; version < cacheSchemaVersion; version++ {
+		data, err = cacheMigrations[version](kind, data)
+		if err != nil {
+			return fmt.Errorf("migrating %s from schema version %d: %w", path, version, err)
+		}
+	}
+	err = json.Unmarshal(data, value)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeCacheFile atomically replaces the JSON cache file at path with value, keeping the previous file as
+// its backup
+func writeCacheFile(path string, kind string, value interface{}, logger *slog.Logger) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+	envelope := cacheEnvelope{
+		SchemaVersion:  cacheSchemaVersion,
+		LibraryVersion: Version,
+		CreatedAt:      time.Now(),
+		Data:           data,
+	}
+
+	file, err := createTempFile(path)
+	if err != nil {
+		return err
+	}
+	tempPath := file.Name()
+	err = json.NewEncoder(file).Encode(envelope)
+	if err != nil {
+		err = fmt.Errorf("encoding %s: %w", path, err)
+	} else {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = backupCacheFile(path, kind, logger)
+	}
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// createTempFile creates a temporary file next to path to be renamed over it. Unlike the files of
+// os.CreateTemp, which only their owner can read, it gets the permissions of the file at path, or those of a
+// new file (0666 less the umask), so that cache files shared by processes of different users stay shared
+func createTempFile(path string) (*os.File, error) {
+	for {
+		tempPath := path + "." + strconv.FormatUint(rand.Uint64(), 36) + ".tmp"
+		file, err := os.OpenFile(tempPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil {
+			if err := file.Chmod(info.Mode().Perm()); err != nil {
+				file.Close()
+				os.Remove(tempPath)
+				return nil, err
+			}
+		}
+		return file, nil
+	}
+}
+
+// backupCacheFile makes the cache file at path its backup, leaving the file itself in place so that it can
+// be loaded until it is replaced. A file which can't be decoded is moved aside instead, so that it isn't
+// lost but doesn't replace the previous backup
+func backupCacheFile(path string, kind string, logger *slog.Logger) error {
+	backupPath := path + backupSuffix
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	var value json.RawMessage
+	if err := decodeCacheFile(path, kind, &value); err != nil {
+		logger.Warn("Moving unreadable cache aside", "path", path, "to", path+corruptSuffix, "error", err)
+		return os.Rename(path, path+corruptSuffix)
+	}
+	if err := os.Remove(backupPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Link(path, backupPath); err != nil {
+		// hard links aren't supported everywhere, the file is briefly missing after a rename instead
+		return os.Rename(path, backupPath)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package go_scholar
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that saving keeps the previous generation as a backup, which is loaded when the cache is truncated
+func TestCacheFileBackup(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+
+	store, _ := NewJSONFileStore(profileCache, articleCache)
+	store.StoreProfile(Profile{User: "first", LastRetrieved: time.Now()})
+	assert.NoError(t, store.Save())
+	store.StoreProfile(Profile{User: "second", LastRetrieved: time.Now()})
+	assert.NoError(t, store.Save())
+
+	// no temporary files are left behind
+	files, _ := filepath.Glob(dir + "/*.tmp")
+	assert.Empty(t, files)
+
+	// simulate a crash mid-write of an older version
+	assert.NoError(t, os.WriteFile(profileCache, []byte(`{"first": {"Us`), 0644))
+	loaded, err := NewJSONFileStore(profileCache, articleCache)
+	assert.NoError(t, err)
+	_, ok, _ := loaded.LoadProfile("first")
+	assert.True(t, ok)
+	_, ok, _ = loaded.LoadProfile("second")
+	assert.False(t, ok, "the backup is the generation before the last save")
+
+	// saving over a corrupt file keeps the good backup
+	assert.NoError(t, loaded.Save())
+	loaded, err = NewJSONFileStore(profileCache+backupSuffix, articleCache)
+	assert.NoError(t, err)
+	_, ok, _ = loaded.LoadProfile("first")
+	assert.True(t, ok)
+}
+
+// Test that two stores saving to the same files keep each other's entries, preferring the newest
+func TestCacheFileMerge(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	first, _ := NewJSONFileStore(profileCache, articleCache)
+	second, _ := NewJSONFileStore(profileCache, articleCache)
+	first.StoreProfile(Profile{User: "first", LastRetrieved: newer})
+	first.StoreProfile(Profile{User: "shared", Name: "newer", LastRetrieved: newer})
+	first.StoreArticle(&Article{ScholarURL: sampleArticleURL, LastRetrieved: newer})
+	second.StoreProfile(Profile{User: "second", LastRetrieved: newer})
+	second.StoreProfile(Profile{User: "shared", Name: "older", LastRetrieved: older})
+	assert.NoError(t, first.Save())
+	assert.NoError(t, second.Save())
+
+	loaded, err := NewJSONFileStore(profileCache, articleCache)
+	assert.NoError(t, err)
+	profiles, _ := loaded.ListProfiles()
+	assert.Len(t, profiles, 3)
+	shared, _, _ := loaded.LoadProfile("shared")
+	assert.Equal(t, "newer", shared.Name)
+	_, ok, _ := loaded.LoadArticle(sampleArticleURL)
+	assert.True(t, ok)
+}
+
+// Test that deleted entries aren't merged back from the files when saving, unless another process saved a
+// more recent version of them
+func TestCacheFileDelete(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	retrieved := time.Now().Add(-time.Hour)
+
+	store, _ := NewJSONFileStore(profileCache, articleCache)
+	store.StoreProfile(Profile{User: "deleted", LastRetrieved: retrieved})
+	store.StoreProfile(Profile{User: "refreshed", LastRetrieved: retrieved})
+	store.StoreProfile(Profile{User: "kept", LastRetrieved: retrieved})
+	store.StoreArticle(&Article{ScholarURL: sampleArticleURL, LastRetrieved: retrieved})
+	store.StoreListing(Listing{Key: "cites:123", LastRetrieved: retrieved})
+	assert.NoError(t, store.Save())
+
+	// another process refreshes one of the profiles after this one loaded the files
+	other, err := NewJSONFileStore(profileCache, articleCache)
+	assert.NoError(t, err)
+	other.StoreProfile(Profile{User: "refreshed", Name: "refreshed", LastRetrieved: time.Now()})
+	assert.NoError(t, other.Save())
+
+	assert.NoError(t, store.DeleteProfile("deleted"))
+	assert.NoError(t, store.DeleteProfile("refreshed"))
+	assert.NoError(t, store.DeleteArticle(sampleArticleURL))
+	assert.NoError(t, store.DeleteListing("cites:123"))
+	assert.NoError(t, store.Save())
+
+	loaded, err := NewJSONFileStore(profileCache, articleCache)
+	assert.NoError(t, err)
+	_, ok, _ := loaded.LoadProfile("deleted")
+	assert.False(t, ok)
+	_, ok, _ = loaded.LoadProfile("kept")
+	assert.True(t, ok)
+	refreshed, ok, _ := loaded.LoadProfile("refreshed")
+	assert.True(t, ok)
+	assert.Equal(t, "refreshed", refreshed.Name)
+	_, ok, _ = loaded.LoadArticle(sampleArticleURL)
+	assert.False(t, ok)
+	_, ok, _ = loaded.LoadListing("cites:123")
+	assert.False(t, ok)
+
+	// an entry stored again after it was deleted is saved
+	store.StoreProfile(Profile{User: "deleted", LastRetrieved: retrieved})
+	assert.NoError(t, store.Save())
+	loaded, _ = NewJSONFileStore(profileCache, articleCache)
+	_, ok, _ = loaded.LoadProfile("deleted")
+	assert.True(t, ok)
+}
+
+// Test that the bare maps written before the versioned envelope are migrated on load
+func TestCacheFileMigration(t *testing.T) {
+	assert.Len(t, cacheMigrations, cacheSchemaVersion, "every schema version needs a migration")
+
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	assert.NoError(t, os.WriteFile(profileCache, []byte(`{"SbUmSEAAAAAJ": {"User": "SbUmSEAAAAAJ", "Name": "Jason Ernst"}}`), 0644))
+	assert.NoError(t, os.WriteFile(articleCache, []byte(`{"`+sampleArticleURL+`": {"Title": "Title"}}`), 0644))
+
+	store, err := NewJSONFileStore(profileCache, articleCache)
+	assert.NoError(t, err)
+	profile, ok, _ := store.LoadProfile("SbUmSEAAAAAJ")
+	assert.True(t, ok)
+	assert.Equal(t, "Jason Ernst", profile.Name)
+	// the links to Google Scholar are made relative to the host
+	article, ok, _ := store.LoadArticle(strings.TrimPrefix(sampleArticleURL, BaseURL))
+	assert.True(t, ok)
+	assert.Equal(t, "Title", article.Title)
+	assert.Equal(t, strings.TrimPrefix(sampleArticleURL, BaseURL), article.ScholarURL)
+
+	assert.NoError(t, store.Save())
+	content, err := os.ReadFile(profileCache)
+	assert.NoError(t, err)
+	var envelope cacheEnvelope
+	assert.NoError(t, json.Unmarshal(content, &envelope))
+	assert.Equal(t, cacheSchemaVersion, envelope.SchemaVersion)
+	assert.Equal(t, Version, envelope.LibraryVersion)
+	assert.False(t, envelope.CreatedAt.IsZero())
+}
+
+// Test that cache files from a newer schema are neither loaded nor overwritten
+func TestCacheFileNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	newer := []byte(`{"SchemaVersion": 99, "LibraryVersion": "9.0.0", "Data": {}}`)
+	assert.NoError(t, os.WriteFile(profileCache, newer, 0644))
+	assert.NoError(t, os.WriteFile(articleCache, newer, 0644))
+
+	store, err := NewJSONFileStore(profileCache, articleCache)
+	assert.ErrorIs(t, err, ErrCacheVersion)
+
+	store.StoreProfile(Profile{User: "SbUmSEAAAAAJ", LastRetrieved: time.Now()})
+	assert.ErrorIs(t, store.Save(), ErrCacheVersion)
+	content, _ := os.ReadFile(profileCache)
+	assert.Equal(t, newer, content)
+}
+
+// Test that an unreadable cache file is moved aside rather than lost when saving over it
+func TestCacheFileCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	corrupt := []byte(`{"SbUmSEAAAAAJ": {"Na`)
+	assert.NoError(t, os.WriteFile(profileCache, corrupt, 0644))
+
+	store, err := NewJSONFileStore(profileCache, articleCache)
+	assert.Error(t, err)
+	assert.NoError(t, store.Save())
+	content, _ := os.ReadFile(profileCache + corruptSuffix)
+	assert.Equal(t, corrupt, content)
+}
+
+// Test that the absolute links to Google Scholar of version 1 caches are made relative, and made absolute
+// again with the base URL of the Scholar they are loaded by
+func TestCacheFileRelativeURLMigration(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	profiles := `{"SchemaVersion": 1, "Data": {"SbUmSEAAAAAJ": {"User": "SbUmSEAAAAAJ", "Name": "Jason Ernst",
+		"PhotoURL": "https://scholar.google.com/citations?view_op=medium_photo&user=SbUmSEAAAAAJ",
+		"Articles": ["` + sampleArticleURL + `"], "LastRetrieved": "2100-01-01T00:00:00Z"}}}`
+	articles := `{"SchemaVersion": 1, "Data": {"` + sampleArticleURL + `": {"Title": "Title", "NumCitations": 12,
+		"URL": "https://ieeexplore.ieee.org/document/1", "ScholarURL": "` + sampleArticleURL + `",
+		"ScholarCitedByURLs": ["https://scholar.google.co.uk/scholar?oi=bibs&hl=en&cites=123"],
+		"LastRetrieved": "2100-01-01T00:00:00Z"}}}`
+	assert.NoError(t, os.WriteFile(profileCache, []byte(profiles), 0644))
+	assert.NoError(t, os.WriteFile(articleCache, []byte(articles), 0644))
+
+	store, err := NewJSONFileStore(profileCache, articleCache)
+	assert.NoError(t, err)
+	relativeURL := strings.TrimPrefix(sampleArticleURL, BaseURL)
+	profile, _, _ := store.LoadProfile("SbUmSEAAAAAJ")
+	assert.Equal(t, "/citations?view_op=medium_photo&user=SbUmSEAAAAAJ", profile.PhotoURL)
+	assert.Equal(t, []string{relativeURL}, profile.Articles)
+	article, ok, _ := store.LoadArticle(relativeURL)
+	assert.True(t, ok)
+	assert.Equal(t, relativeURL, article.ScholarURL)
+	assert.Equal(t, 12, article.NumCitations)
+	assert.Equal(t, "https://ieeexplore.ieee.org/document/1", article.URL)
+	assert.Equal(t, []string{"/scholar?oi=bibs&hl=en&cites=123"}, article.ScholarCitedByURLs)
+
+	sch, err := NewWithOptions(WithStore(store), WithBaseURL("https://scholar.google.de"), WithHTTPClient(&MockAlwaysFailHTTPClient{}))
+	assert.NoError(t, err)
+	cached, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+	assert.Len(t, cached, 1)
+	assert.Equal(t, "https://scholar.google.de"+relativeURL, cached[0].ScholarURL)
+	assert.Equal(t, []string{"https://scholar.google.de/scholar?oi=bibs&hl=en&cites=123"}, cached[0].ScholarCitedByURLs)
+}
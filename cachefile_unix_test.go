@@ -0,0 +1,33 @@
+//go:build unix
+
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// Test that saved cache files keep the permissions of the files they replace, and that new ones get those
+// of a new file rather than being readable only by their owner
+func TestCacheFileMode(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	umask := syscall.Umask(0022)
+	defer syscall.Umask(umask)
+
+	store, _ := NewJSONFileStore(profileCache, articleCache)
+	store.StoreProfile(Profile{User: "SbUmSEAAAAAJ"})
+	assert.NoError(t, store.Save())
+	info, err := os.Stat(profileCache)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	assert.NoError(t, os.Chmod(profileCache, 0664))
+	assert.NoError(t, store.Save())
+	info, err = os.Stat(profileCache)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0664), info.Mode().Perm())
+}
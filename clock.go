@@ -0,0 +1,21 @@
+package go_scholar
+
+import "time"
+
+// Clock is the source of the current time, used for the cache timestamps and expiry, and of the waits between
+// requests. It can be replaced with WithClock, e.g. to control time in tests
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the Clock of the time package
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,90 @@
+package go_scholar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrRateLimited is returned when Google Scholar is still rate limiting requests (HTTP 429) after
+	// retrying. Use errors.As with a *RateLimitError for the retry information
+	ErrRateLimited = errors.New("Scholar: rate limited")
+	// ErrNotFound is returned when a page doesn't exist, e.g. the profile of a bad user id
+	ErrNotFound = errors.New("Scholar: not found")
+	// ErrBlocked is returned when Google Scholar refuses to serve requests
+	ErrBlocked = errors.New("Scholar: blocked")
+	// ErrLayoutChanged is returned when a page doesn't have the structure the parser expects, which
+	// usually means Google Scholar changed its layout
+	ErrLayoutChanged = errors.New("Scholar: unexpected page layout")
+	// ErrCacheVersion is returned when a cache file was written by a newer version of the library with a
+	// schema this version can't read. Such files are not overwritten by SaveCache
+	ErrCacheVersion = errors.New("Scholar: unsupported cache schema version")
+)
+
+// RateLimitError is returned when the retries for a rate limited request are exhausted, it matches
+// ErrRateLimited with errors.Is
+type RateLimitError struct {
+	URL         string
+	Attempts    int           // number of requests made
+	RetryAfter  time.Duration // suggested wait before trying again, from the Retry-After or rate limit headers if any
+	NextAllowed time.Time     // time from which requests may be made again, the last response's time plus RetryAfter
+	Remaining   string        // value of the x-ratelimit-remaining (or RateLimit-Remaining) header, if any
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("Scholar: rate limited (HTTP 429) after %d attempts from URL: %s, retry after %v (at %s)",
+		e.Attempts, e.URL, e.RetryAfter, e.NextAllowed.Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// BlockedError is returned when Google Scholar serves a block page (a CAPTCHA, an unusual traffic notice or
+// a consent page) in place of the requested page, it matches ErrBlocked with errors.Is
+type BlockedError struct {
+	URL    string
+	Reason string // "captcha", "unusual traffic" or "consent"
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("Scholar: blocked by %s page from URL: %s", e.Reason, e.URL)
+}
+
+func (e *BlockedError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+// StatusError is returned for responses with an unexpected HTTP status code. It matches ErrNotFound for
+// 404 and 410, ErrBlocked for 403 and ErrRateLimited for 429 with errors.Is
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Scholar: HTTP Status Code from URL: %s %d %s", e.URL, e.StatusCode, e.Status)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrBlocked:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// checkStatus returns a *StatusError if the response isn't a 200
+func checkStatus(resp *http.Response, requestURL string) error {
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{URL: requestURL, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
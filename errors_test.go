@@ -0,0 +1,78 @@
+package go_scholar
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// MockStatusHTTPClient returns the same status code and body for every request
+type MockStatusHTTPClient struct {
+	statusCode int
+	body       string
+}
+
+func (m *MockStatusHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: m.statusCode,
+		Status:     http.StatusText(m.statusCode),
+		Body:       io.NopCloser(strings.NewReader(m.body)),
+	}, nil
+}
+
+func TestStatusErrors(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+
+	sch.SetHTTPClient(&MockStatusHTTPClient{statusCode: 404})
+	_, err := sch.QueryProfile("bad-user", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, 404, statusErr.StatusCode)
+
+	sch.SetHTTPClient(&MockStatusHTTPClient{statusCode: 403})
+	_, err = sch.QueryArticle(sampleArticleURL, &Article{}, false)
+	assert.ErrorIs(t, err, ErrBlocked)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func TestLayoutChangedErrors(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockStatusHTTPClient{statusCode: 200, body: "<html><body></body></html>"})
+
+	_, err := sch.QueryProfile("SbUmSEAAAAAJ", 1)
+	assert.ErrorIs(t, err, ErrLayoutChanged)
+
+	_, err = sch.QueryArticle(sampleArticleURL, &Article{}, false)
+	assert.ErrorIs(t, err, ErrLayoutChanged)
+}
+
+func TestRateLimitError(t *testing.T) {
+	var err error = &RateLimitError{URL: BaseURL, Attempts: 4, RetryAfter: 40 * time.Second}
+	assert.ErrorIs(t, err, ErrRateLimited)
+	var rateLimitErr *RateLimitError
+	assert.True(t, errors.As(err, &rateLimitErr))
+	assert.Equal(t, 40*time.Second, rateLimitErr.RetryAfter)
+}
+
+func TestCacheErrors(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+
+	dir := t.TempDir()
+	err := sch.LoadCache(dir+"/missing-profiles.json", dir+"/missing-articles.json")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	err = sch.SaveCache(dir+"/missing/profiles.json", dir+"/missing/articles.json")
+	var pathErr *fs.PathError
+	assert.True(t, errors.As(err, &pathErr))
+
+	assert.NoError(t, sch.SaveCache(dir+"/profiles.json", dir+"/articles.json"))
+	assert.NoError(t, sch.LoadCache(dir+"/profiles.json", dir+"/articles.json"))
+}
@@ -0,0 +1,121 @@
+package go_scholar
+
+import (
+	"context"
+	"net/url"
+)
+
+// QueryCitedBy returns up to limit articles (a single page if limit <= 0) citing the article at articleURL (a
+// Scholar article URL as used by QueryArticle), following its cited by links through the results pages. The
+// article is looked up in the article cache, or queried if it isn't cached. The citing articles are cached
+// by the cluster id of the cited article, so repeated crawls of the same article don't make any requests
+// until the cache expires
+func (sch *Scholar) QueryCitedBy(articleURL string, limit int) ([]*Article, error) {
+	return sch.QueryCitedByContext(context.Background(), articleURL, limit)
+}
+
+// QueryCitedByContext is QueryCitedBy with a context which can cancel the requests and the waits between them
+func (sch *Scholar) QueryCitedByContext(ctx context.Context, articleURL string, limit int) ([]*Article, error) {
+	return sch.queryLinks(ctx, articleURL, limit, "cites")
+}
+
+// QueryVersions returns up to limit versions of the article at articleURL (e.g. preprints and publisher
+// versions, which may be free to read), following its versions links like QueryCitedBy
+func (sch *Scholar) QueryVersions(articleURL string, limit int) ([]*Article, error) {
+	return sch.QueryVersionsContext(context.Background(), articleURL, limit)
+}
+
+// QueryVersionsContext is QueryVersions with a context which can cancel the requests and the waits between them
+func (sch *Scholar) QueryVersionsContext(ctx context.Context, articleURL string, limit int) ([]*Article, error) {
+	return sch.queryLinks(ctx, articleURL, limit, "cluster")
+}
+
+// QueryRelated returns up to limit articles related to the article at articleURL, following its related
+// articles links like QueryCitedBy
+func (sch *Scholar) QueryRelated(articleURL string, limit int) ([]*Article, error) {
+	return sch.QueryRelatedContext(context.Background(), articleURL, limit)
+}
+
+// QueryRelatedContext is QueryRelated with a context which can cancel the requests and the waits between them
+func (sch *Scholar) QueryRelatedContext(ctx context.Context, articleURL string, limit int) ([]*Article, error) {
+	return sch.queryLinks(ctx, articleURL, limit, "q")
+}
+
+// queryLinks follows the links of an article to the results pages selected by the parameter identifying
+// them: cites for the cited by links, cluster for the versions links and q for the related articles links
+func (sch *Scholar) queryLinks(ctx context.Context, articleURL string, limit int, param string) ([]*Article, error) {
+	limit = pageLimit(limit, searchPageSize)
+	article, err := sch.loadArticle(ctx, articleURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []string
+	switch param {
+	case "cites":
+		links = article.ScholarCitedByURLs
+	case "cluster":
+		links = article.ScholarVersionsURLs
+	case "q":
+		links = article.ScholarRelatedURLs
+	}
+
+	var results []*Article
+	// an article may be merged from several Scholar articles (e.g. a book), each with its own links
+	for _, link := range links {
+		value := linkParam(link, param)
+		if value == "" {
+			continue
+		}
+		params := url.Values{}
+		params.Set(param, value)
+		params.Set("hl", "en")
+		articles, err := sch.queryListing(ctx, params, limit-len(results), param+":"+value)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, articles...)
+		if len(results) >= limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// loadArticle returns the article at articleURL from the article cache, querying and caching it on a miss.
+// An expired article which fails to refresh is served stale
+func (sch *Scholar) loadArticle(ctx context.Context, articleURL string) (*Article, error) {
+	cacheArticle, articleOk := sch.cachedArticle(articleURL)
+	if articleOk {
+		if !sch.expired(sch.cacheTTL.Article, articleURL, cacheArticle.LastRetrieved) {
+			sch.logger.Debug("Cache hit for article", "url", articleURL)
+			return cacheArticle, nil
+		}
+		sch.logger.Debug("Cache expired for article", "url", articleURL, "last_retrieved", cacheArticle.LastRetrieved)
+	} else {
+		sch.logger.Debug("Cache miss for article", "url", articleURL)
+	}
+	article, err := sch.QueryArticleContext(ctx, articleURL, &Article{}, false)
+	if err != nil {
+		if articleOk && ctx.Err() == nil {
+			return cacheArticle, nil
+		}
+		return nil, err
+	}
+	sch.cacheArticle(article)
+	return article, nil
+}
+
+// citesClusterID returns the cluster id of the cited article from a cited by URL (/scholar?cites=...)
+func citesClusterID(citedByURL string) string {
+	return linkParam(citedByURL, "cites")
+}
+
+// linkParam returns the value of a parameter of a link, or an empty string if it can't be parsed
+func linkParam(link string, param string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return parsed.Query().Get(param)
+}
@@ -0,0 +1,121 @@
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleArticleURL = "https://scholar.google.com/citations?view_op=view_citation&hl=en&user=SbUmSEAAAAAJ&citation_for_view=SbUmSEAAAAAJ:HoB7MX3m0LUC"
+
+// Test that the links of the "Scholar articles" field are parsed from the sample article page
+func TestArticleScholarLinks(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	article, err := sch.QueryArticle(sampleArticleURL, &Article{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, article.Articles)
+	assert.Equal(t, "16589742970128240413", article.ClusterID)
+	assert.Len(t, article.ScholarCitedByURLs, 1)
+	assert.Len(t, article.ScholarRelatedURLs, 1)
+	assert.Len(t, article.ScholarVersionsURLs, 1)
+}
+
+func TestQueryCitedBy(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	mockClient := &MockRecordingHTTPClient{}
+	sch.SetHTTPClient(mockClient)
+
+	citing, err := sch.QueryCitedBy(sampleArticleURL, 2)
+	assert.NoError(t, err)
+	assert.Len(t, citing, 2)
+	assert.Equal(t, "Decentralized applications: The blockchain-empowered software system", citing[0].Title)
+
+	// the article page and the first results page
+	assert.Len(t, mockClient.urls, 2)
+	assert.True(t, strings.Contains(mockClient.urls[1], "cites=16589742970128240413"))
+
+	// the article and the citing articles are served from the cache
+	citing, err = sch.QueryCitedBy(sampleArticleURL, 10)
+	assert.NoError(t, err)
+	assert.Len(t, citing, 3)
+	assert.Len(t, mockClient.urls, 2)
+}
+
+func TestQueryVersionsAndRelated(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	mockClient := &MockRecordingHTTPClient{}
+	sch.SetHTTPClient(mockClient)
+
+	versions, err := sch.QueryVersions(sampleArticleURL, 10)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 3)
+	assert.Len(t, mockClient.urls, 2)
+	assert.True(t, strings.Contains(mockClient.urls[1], "cluster=16589742970128240413"))
+
+	related, err := sch.QueryRelated(sampleArticleURL, 10)
+	assert.NoError(t, err)
+	assert.Len(t, related, 3)
+	// the article is served from the cache
+	assert.Len(t, mockClient.urls, 3)
+	requestURL, err := url.Parse(mockClient.urls[2])
+	assert.NoError(t, err)
+	assert.Equal(t, "related:HQ-HyVWbOuYJ:scholar.google.com/", requestURL.Query().Get("q"))
+}
+
+// Test that the listings of links are saved with the cache, so a new process doesn't fetch them again
+func TestLinksCachePersisted(t *testing.T) {
+	dir := t.TempDir()
+	sch := New(dir+"/profiles.json", dir+"/articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+	citing, err := sch.QueryCitedBy(sampleArticleURL, 10)
+	assert.NoError(t, err)
+	assert.Len(t, citing, 3)
+	assert.NoError(t, sch.SaveCache(dir+"/profiles.json", dir+"/articles.json"))
+	assert.FileExists(t, dir+"/articles.listings.json")
+
+	reloaded := New(dir+"/profiles.json", dir+"/articles.json")
+	reloaded.SetRequestDelay(1 * time.Millisecond)
+	reloaded.SetHTTPClient(&MockAlwaysFailHTTPClient{})
+	cached, err := reloaded.QueryCitedBy(sampleArticleURL, 10)
+	assert.NoError(t, err)
+	assertSameArticles(t, citing, cached)
+
+	// the listings of the bolt store are written as they are fetched
+	store, err := OpenBoltStore(dir + "/cache.db")
+	assert.NoError(t, err)
+	sch, err = NewWithOptions(WithStore(store), WithHTTPClient(&MockHTTPClient{}), WithRequestDelay(time.Millisecond))
+	assert.NoError(t, err)
+	versions, err := sch.QueryVersions(sampleArticleURL, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Close())
+
+	store, err = OpenBoltStore(dir + "/cache.db")
+	assert.NoError(t, err)
+	defer store.Close()
+	listings, err := store.ListListings()
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster:16589742970128240413", listings[0].Key)
+	sch, err = NewWithOptions(WithStore(store), WithHTTPClient(&MockAlwaysFailHTTPClient{}))
+	assert.NoError(t, err)
+	cached, err = sch.QueryVersions(sampleArticleURL, 10)
+	assert.NoError(t, err)
+	assertSameArticles(t, versions, cached)
+}
+
+// assertSameArticles asserts that the articles read back from a cache are those which were cached
+func assertSameArticles(t *testing.T, expected []*Article, actual []*Article) {
+	assert.Len(t, actual, len(expected))
+	for i := range min(len(expected), len(actual)) {
+		assert.Equal(t, expected[i].Title, actual[i].Title)
+		assert.Equal(t, expected[i].ScholarCitedByURLs, actual[i].ScholarCitedByURLs)
+		assert.True(t, expected[i].LastRetrieved.Equal(actual[i].LastRetrieved))
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package go_scholar
+
+// lockFile is a no-op where flock isn't available, the cache files are still replaced atomically but
+// concurrent saves from several processes aren't serialized
+func lockFile(path string, exclusive bool) (unlock func(), err error) {
+	return func() {}, nil
+}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// Test that a save waits for the lock held by another process
+func TestCacheFileLock(t *testing.T) {
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+
+	store, _ := NewJSONFileStore(profileCache, articleCache)
+	unlock, err := lockFile(profileCache+lockSuffix, true)
+	assert.NoError(t, err)
+
+	saved := make(chan error)
+	go func() {
+		saved <- store.Save()
+	}()
+	select {
+	case <-saved:
+		t.Fatal("save didn't wait for the lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	assert.NoError(t, <-saved)
+}
@@ -0,0 +1,16 @@
+package go_scholar
+
+import (
+	"log/slog"
+)
+
+// discardLogger is the logger of a new Scholar, which doesn't write anything
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// orDiscard returns logger, or the discard logger if it is nil
+func orDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return discardLogger
+	}
+	return logger
+}
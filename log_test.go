@@ -0,0 +1,77 @@
+package go_scholar
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// Test that cache hits and misses are logged with structured attributes
+func TestWithLogger(t *testing.T) {
+	var output bytes.Buffer
+	sch, err := NewWithOptions(WithStore(NewMemoryStore()), WithHTTPClient(&MockHTTPClient{}),
+		WithRequestDelay(time.Millisecond),
+		WithLogger(slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	assert.NoError(t, err)
+
+	_, err = sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+	_, err = sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+
+	var messages []string
+	decoder := json.NewDecoder(&output)
+	for decoder.More() {
+		var record map[string]interface{}
+		assert.NoError(t, decoder.Decode(&record))
+		messages = append(messages, record["msg"].(string))
+		if record["msg"] == "Profile cache miss" || record["msg"] == "Profile cache hit" {
+			assert.Equal(t, "SbUmSEAAAAAJ", record["user"])
+		}
+		if record["msg"] == "Cache hit for article" {
+			assert.Contains(t, record["url"], "view_citation")
+		}
+	}
+	assert.Contains(t, messages, "Profile cache miss")
+	assert.Contains(t, messages, "Profile cache hit")
+	assert.Contains(t, messages, "Cache hit for article")
+
+	// a nil logger discards the messages
+	output.Reset()
+	sch, err = NewWithOptions(WithStore(NewMemoryStore()), WithHTTPClient(&MockHTTPClient{}),
+		WithRequestDelay(time.Millisecond), WithLogger(nil))
+	assert.NoError(t, err)
+	_, err = sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+	assert.Empty(t, output.String())
+}
+
+// Test that the deprecated setters are ignored, with a warning, by a Scholar created by NewWithOptions
+func TestSettersIgnoredWithOptions(t *testing.T) {
+	var output bytes.Buffer
+	mockClient := &MockCountingHTTPClient{}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithRequestDelay(time.Millisecond),
+		WithLogger(slog.New(slog.NewJSONHandler(&output, nil))))
+	assert.NoError(t, err)
+
+	sch.SetHTTPClient(&MockAlwaysFailHTTPClient{})
+	sch.SetRequestDelay(time.Minute)
+	assert.Equal(t, time.Millisecond, sch.CurrentDelay())
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mockClient.callCount)
+
+	var setters []string
+	decoder := json.NewDecoder(&output)
+	for decoder.More() {
+		var record map[string]interface{}
+		assert.NoError(t, decoder.Decode(&record))
+		if record["msg"] == "Ignoring setter of a Scholar created by NewWithOptions" {
+			setters = append(setters, record["setter"].(string))
+		}
+	}
+	assert.Equal(t, []string{"SetHTTPClient", "SetRequestDelay"}, setters)
+}
@@ -0,0 +1,185 @@
+package go_scholar
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// default number of retries of a failed request
+const defaultMaxRetries = 3
+
+// Option configures a Scholar created by NewWithOptions
+type Option func(*Scholar) error
+
+// NewWithOptions returns a Scholar configured by opts, which are validated up front. Unlike New, the cache is
+// only kept in memory unless a store is given with WithStore, and nothing is loaded from or written to disk.
+// The Scholar's configuration can't be changed once it is created, the deprecated setters such as
+// SetHTTPClient are ignored (with a warning)
+func NewWithOptions(opts ...Option) (*Scholar, error) {
+	sch := NewWithStore(NewMemoryStore())
+	for _, opt := range opts {
+		if err := opt(sch); err != nil {
+			return nil, err
+		}
+	}
+	if sch.throttle != nil {
+		sch.requestDelay = sch.throttle.clamp(sch.requestDelay)
+	}
+	sch.immutable = true
+	return sch, nil
+}
+
+// WithHTTPClient sets the HTTP client requests are made with
+func WithHTTPClient(client HTTPClient) Option {
+	return func(sch *Scholar) error {
+		if client == nil {
+			return errors.New("Scholar: nil HTTP client")
+		}
+		sch.httpClient = client
+		return nil
+	}
+}
+
+// WithBaseURL sets the scheme and host requests are made to, BaseURL by default. It may be a regional
+// Google Scholar host, a mirror, or a local server in tests
+func WithBaseURL(baseURL string) Option {
+	return func(sch *Scholar) error {
+		parsed, err := url.Parse(baseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("Scholar: invalid base URL %q", baseURL)
+		}
+		sch.baseURL = strings.TrimSuffix(baseURL, "/")
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests, AGENT by default
+func WithUserAgent(userAgent string) Option {
+	return func(sch *Scholar) error {
+		if strings.TrimSpace(userAgent) == "" {
+			return errors.New("Scholar: empty user agent")
+		}
+		sch.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithRequestDelay sets the delay between requests, 2 seconds by default
+func WithRequestDelay(delay time.Duration) Option {
+	return func(sch *Scholar) error {
+		if delay < 0 {
+			return fmt.Errorf("Scholar: negative request delay %v", delay)
+		}
+		sch.requestDelay = delay
+		return nil
+	}
+}
+
+// WithAdaptiveThrottle adapts the delay between requests to how Google Scholar responds (see AdaptiveThrottle),
+// starting from the request delay brought within the bounds of throttle. The delay is fixed by default
+func WithAdaptiveThrottle(throttle AdaptiveThrottle) Option {
+	return func(sch *Scholar) error {
+		if err := throttle.validate(); err != nil {
+			return err
+		}
+		sch.throttle = &throttle
+		return nil
+	}
+}
+
+// WithMaxRetries sets how many times a failed request is retried, 3 by default. It sets the MaxRetries of the
+// retry policy, so it is overridden by a later WithRetryPolicy
+func WithMaxRetries(maxRetries int) Option {
+	return func(sch *Scholar) error {
+		if maxRetries < 0 {
+			return fmt.Errorf("Scholar: negative max retries %d", maxRetries)
+		}
+		sch.retryPolicy.MaxRetries = maxRetries
+		return nil
+	}
+}
+
+// WithRetryPolicy sets which failed requests are retried and how, DefaultRetryPolicy by default
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(sch *Scholar) error {
+		if policy.MaxRetries < 0 {
+			return fmt.Errorf("Scholar: negative max retries %d", policy.MaxRetries)
+		}
+		for _, statusCode := range policy.StatusCodes {
+			if statusCode < 100 || statusCode > 599 {
+				return fmt.Errorf("Scholar: invalid retry status code %d", statusCode)
+			}
+		}
+		policy.StatusCodes = slices.Clone(policy.StatusCodes)
+		sch.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithMaxRetryWait sets the longest wait asked for by the Retry-After or rate limit headers of a rate limited
+// response which is waited out before retrying, 5 minutes by default. A request asked to wait longer fails at
+// once with a *RateLimitError whose NextAllowed is the time it may be retried, and the next requests are held
+// back for the max retry wait
+func WithMaxRetryWait(maxWait time.Duration) Option {
+	return func(sch *Scholar) error {
+		if maxWait < 0 {
+			return fmt.Errorf("Scholar: negative max retry wait %v", maxWait)
+		}
+		sch.maxRetryWait = maxWait
+		return nil
+	}
+}
+
+// WithCacheTTL sets how long cached entries are served before they are refreshed, DefaultCacheTTL by default.
+// The TTLs left out of ttl keep their defaults
+func WithCacheTTL(ttl CacheTTL) Option {
+	return func(sch *Scholar) error {
+		for _, d := range []time.Duration{ttl.Profile, ttl.Article, ttl.Search} {
+			if d < 0 && d != NeverExpire && d != AlwaysRefresh {
+				return fmt.Errorf("Scholar: invalid cache TTL %v", d)
+			}
+		}
+		if ttl.Jitter < 0 || ttl.Jitter >= 1 {
+			return fmt.Errorf("Scholar: invalid cache TTL jitter %v, must be in [0, 1)", ttl.Jitter)
+		}
+		sch.cacheTTL = ttl.withDefaults()
+		return nil
+	}
+}
+
+// WithStore sets the storage of the profile and article caches, a new MemoryStore by default
+func WithStore(store Store) Option {
+	return func(sch *Scholar) error {
+		if store == nil {
+			return errors.New("Scholar: nil store")
+		}
+		sch.store = store
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the cache hits and misses, retries and errors, which is discarded by default.
+// Messages have attributes such as user, url, attempt and delay. Cache hits and misses and the pages dumped by
+// QueryProfileDumpResponse are logged at the debug level. A nil logger discards the messages
+func WithLogger(logger *slog.Logger) Option {
+	return func(sch *Scholar) error {
+		sch.logger = orDiscard(logger)
+		return nil
+	}
+}
+
+// WithClock sets the source of the time and of the waits between requests, the system clock by default
+func WithClock(clock Clock) Option {
+	return func(sch *Scholar) error {
+		if clock == nil {
+			return errors.New("Scholar: nil clock")
+		}
+		sch.clock = clock
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockClock is a Clock whose time only moves when advanced, and whose waits return at once after advancing
+// the time by the duration waited
+type MockClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	waited []time.Duration
+}
+
+func (c *MockClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *MockClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	c.waited = append(c.waited, d)
+	after := make(chan time.Time, 1)
+	after <- c.now
+	return after
+}
+
+func (c *MockClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// MockUserAgentHTTPClient records the User-Agent headers of the requests made through MockHTTPClient
+type MockUserAgentHTTPClient struct {
+	MockRecordingHTTPClient
+	userAgents []string
+}
+
+func (m *MockUserAgentHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.userAgents = append(m.userAgents, req.Header.Get("User-Agent"))
+	return m.MockRecordingHTTPClient.Do(req)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	mockClient := &MockUserAgentHTTPClient{}
+	store := NewMemoryStore()
+	sch, err := NewWithOptions(
+		WithHTTPClient(mockClient),
+		WithBaseURL("http://localhost:8080/"),
+		WithUserAgent("test-agent"),
+		WithRequestDelay(time.Millisecond),
+		WithStore(store),
+	)
+	assert.NoError(t, err)
+
+	info, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Jason Ernst", info.Name)
+	assert.Len(t, mockClient.urls, 1)
+	assert.True(t, strings.HasPrefix(mockClient.urls[0], "http://localhost:8080/citations?user=SbUmSEAAAAAJ"))
+	assert.Equal(t, []string{"test-agent"}, mockClient.userAgents)
+
+	articles, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(articles[0].ScholarURL, "http://localhost:8080/citations?view_op=view_citation"))
+	_, ok, _ := store.LoadProfile("SbUmSEAAAAAJ")
+	assert.True(t, ok)
+}
+
+func TestNewWithOptionsValidation(t *testing.T) {
+	invalid := []Option{
+		WithHTTPClient(nil),
+		WithBaseURL("scholar.google.com"),
+		WithBaseURL("ftp://scholar.google.com"),
+		WithUserAgent(" "),
+		WithRequestDelay(-time.Second),
+		WithMaxRetries(-1),
+		WithCacheTTL(CacheTTL{Profile: -2 * time.Second}),
+		WithCacheTTL(CacheTTL{Search: -3}),
+		WithCacheTTL(CacheTTL{Jitter: 1}),
+		WithStore(nil),
+		WithClock(nil),
+	}
+	for _, option := range invalid {
+		sch, err := NewWithOptions(option)
+		assert.Error(t, err)
+		assert.Nil(t, sch)
+	}
+
+	sch, err := NewWithOptions(WithCacheTTL(CacheTTL{Profile: NeverExpire, Article: AlwaysRefresh, Jitter: 0.5}), WithLogger(nil))
+	assert.NoError(t, err)
+	assert.NotNil(t, sch)
+}
+
+// Test that the clock is used for the waits between requests and for the cache expiry
+func TestWithClock(t *testing.T) {
+	clock := &MockClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mockClient := &MockCountingHTTPClient{}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithRequestDelay(time.Minute))
+	assert.NoError(t, err)
+
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	profile, _, _ := sch.store.LoadProfile("SbUmSEAAAAAJ")
+	assert.True(t, profile.LastRetrieved.IsZero(), "QueryProfileInfo doesn't cache a new profile")
+
+	_, err = sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+	profile, _, _ = sch.store.LoadProfile("SbUmSEAAAAAJ")
+	assert.Equal(t, clock.Now(), profile.LastRetrieved)
+	assert.NotEmpty(t, clock.waited)
+	for _, waited := range clock.waited {
+		assert.LessOrEqual(t, waited, time.Minute)
+	}
+
+	// the profile is served from the cache until the clock passes its TTL
+	requests := mockClient.callCount
+	clock.Advance(DefaultCacheTTL.Profile - time.Hour)
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, requests, mockClient.callCount)
+	clock.Advance(2 * time.Hour)
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, requests+1, mockClient.callCount)
+}
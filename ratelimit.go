@@ -0,0 +1,71 @@
+package go_scholar
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// default longest wait asked for by a response that is waited out before retrying, see WithMaxRetryWait
+const defaultMaxRetryWait = 5 * time.Minute
+
+// timestamps above this are Unix times in rate limit reset headers, below it they are seconds from now
+const unixResetThreshold = 1000000000
+
+// retryAfter returns how long the headers of resp ask to wait before the next request. That is the
+// Retry-After header (in seconds or as an HTTP date) of an error response, or else the reset of the rate
+// limit window (x-ratelimit-reset or RateLimit-Reset, in seconds or as a Unix time) when the response is a
+// 429 or no requests remain in the window
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode >= 400 {
+		if value := strings.TrimSpace(resp.Header.Get("Retry-After")); value != "" {
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return nonNegative(time.Duration(seconds) * time.Second), true
+			}
+			if date, err := http.ParseTime(value); err == nil {
+				return nonNegative(date.Sub(now)), true
+			}
+		}
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && rateLimitRemaining(resp.Header) != "0" {
+		return 0, false
+	}
+	for _, name := range []string{"x-ratelimit-reset", "RateLimit-Reset"} {
+		value := strings.TrimSpace(resp.Header.Get(name))
+		seconds, err := strconv.ParseFloat(value, 64)
+		if value == "" || err != nil {
+			continue
+		}
+		if seconds > unixResetThreshold {
+			return nonNegative(time.Unix(0, int64(seconds*float64(time.Second))).Sub(now)), true
+		}
+		return nonNegative(time.Duration(seconds * float64(time.Second))), true
+	}
+	return 0, false
+}
+
+// rateLimitRemaining returns the number of requests remaining in the rate limit window from the
+// x-ratelimit-remaining or RateLimit-Remaining header, or an empty string if there is neither
+func rateLimitRemaining(header http.Header) string {
+	if remaining := header.Get("x-ratelimit-remaining"); remaining != "" {
+		return strings.TrimSpace(remaining)
+	}
+	return strings.TrimSpace(header.Get("RateLimit-Remaining"))
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// delayRequestsUntil holds back the requests of the Scholar, including those of other goroutines, until t
+func (sch *Scholar) delayRequestsUntil(t time.Time) {
+	sch.requestMutex.Lock()
+	defer sch.requestMutex.Unlock()
+	if t.After(sch.nextAllowed) {
+		sch.nextAllowed = t
+	}
+}
@@ -0,0 +1,158 @@
+package go_scholar
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// MockSequenceHTTPClient returns its responses in order, then those of MockHTTPClient
+type MockSequenceHTTPClient struct {
+	responses []*http.Response
+	callCount int
+}
+
+func (m *MockSequenceHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.callCount++
+	if len(m.responses) > 0 {
+		resp := m.responses[0]
+		m.responses = m.responses[1:]
+		return resp, nil
+	}
+	return (&MockHTTPClient{}).Do(req)
+}
+
+// mockHeaderResponse returns an empty response with the status code and headers (pairs of name and value)
+func mockHeaderResponse(statusCode int, headers ...string) *http.Response {
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		resp.Header.Set(headers[i], headers[i+1])
+	}
+	return resp
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		resp    *http.Response
+		wait    time.Duration
+		hasWait bool
+	}{
+		{mockHeaderResponse(429), 0, false},
+		{mockHeaderResponse(429, "Retry-After", "120"), 2 * time.Minute, true},
+		{mockHeaderResponse(429, "Retry-After", "Mon, 01 Jan 2024 12:00:30 GMT"), 30 * time.Second, true},
+		{mockHeaderResponse(429, "Retry-After", "Mon, 01 Jan 2024 11:00:00 GMT"), 0, true},
+		{mockHeaderResponse(503, "Retry-After", "10"), 10 * time.Second, true},
+		{mockHeaderResponse(429, "Retry-After", "soon"), 0, false},
+		{mockHeaderResponse(429, "x-ratelimit-reset", "15"), 15 * time.Second, true},
+		{mockHeaderResponse(429, "RateLimit-Reset", "1.5"), 1500 * time.Millisecond, true},
+		{mockHeaderResponse(429, "x-ratelimit-reset", "1704110460"), time.Minute, true},
+		{mockHeaderResponse(200, "x-ratelimit-remaining", "0", "x-ratelimit-reset", "40"), 40 * time.Second, true},
+		{mockHeaderResponse(200, "RateLimit-Remaining", "0", "RateLimit-Reset", "40"), 40 * time.Second, true},
+		{mockHeaderResponse(200, "x-ratelimit-remaining", "3", "x-ratelimit-reset", "40"), 0, false},
+		{mockHeaderResponse(200, "Retry-After", "40"), 0, false},
+	}
+	for _, test := range tests {
+		wait, hasWait := retryAfter(test.resp, now)
+		assert.Equal(t, test.hasWait, hasWait, "headers %v", test.resp.Header)
+		assert.Equal(t, test.wait, wait, "headers %v", test.resp.Header)
+	}
+}
+
+// Test that a rate limited request is retried after the wait its response asks for
+func TestRetryAfterWait(t *testing.T) {
+	clock := &MockClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	mockClient := &MockSequenceHTTPClient{responses: []*http.Response{
+		mockHeaderResponse(429, "Retry-After", "7"),
+		mockHeaderResponse(429, "Retry-After", "Mon, 01 Jan 2024 12:00:37 GMT"),
+	}}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithRequestDelay(0))
+	assert.NoError(t, err)
+
+	info, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Jason Ernst", info.Name)
+	assert.Equal(t, 3, mockClient.callCount)
+	assert.Equal(t, []time.Duration{7 * time.Second, 30 * time.Second}, clock.waited)
+}
+
+// Test that a request asked to wait longer than the max retry wait fails at once with the time it may be retried
+func TestRetryAfterTooLong(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &MockClock{now: now}
+	mockClient := &MockSequenceHTTPClient{responses: []*http.Response{
+		mockHeaderResponse(429, "Retry-After", "3600", "x-ratelimit-remaining", "0"),
+	}}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithMaxRetryWait(time.Minute))
+	assert.NoError(t, err)
+
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	var rateLimitErr *RateLimitError
+	assert.True(t, errors.As(err, &rateLimitErr))
+	assert.Equal(t, 1, rateLimitErr.Attempts)
+	assert.Equal(t, time.Hour, rateLimitErr.RetryAfter)
+	assert.True(t, now.Add(time.Hour).Equal(rateLimitErr.NextAllowed))
+	assert.Equal(t, "0", rateLimitErr.Remaining)
+	assert.Empty(t, clock.waited)
+
+	// the next request is held back, up to the max retry wait
+	_, err = sch.QueryArticle(sampleArticleURL, &Article{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Minute}, clock.waited)
+
+	_, err = NewWithOptions(WithMaxRetryWait(-time.Second))
+	assert.Error(t, err)
+}
+
+// Test that the max retry wait only applies to the waits asked for, the backoff of responses without them is
+// retried until the attempts run out
+func TestMaxRetryWaitBackoff(t *testing.T) {
+	for _, statusCode := range []int{429, 503} {
+		clock := &MockClock{}
+		mockClient := &MockSequenceHTTPClient{responses: []*http.Response{
+			mockHeaderResponse(statusCode),
+			mockHeaderResponse(statusCode),
+			mockHeaderResponse(statusCode),
+			mockHeaderResponse(statusCode),
+		}}
+		sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithMaxRetryWait(time.Second))
+		assert.NoError(t, err)
+
+		_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+		assert.Error(t, err)
+		assert.Equal(t, DefaultRetryPolicy.MaxRetries+1, mockClient.callCount, "status %d", statusCode)
+		if statusCode == 429 {
+			var rateLimitErr *RateLimitError
+			assert.True(t, errors.As(err, &rateLimitErr))
+			assert.Equal(t, DefaultRetryPolicy.MaxRetries+1, rateLimitErr.Attempts)
+		}
+	}
+}
+
+// Test that requests are held back until the rate limit window is reset when none remain in it
+func TestRateLimitWindow(t *testing.T) {
+	clock := &MockClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	profile, err := (&MockHTTPClient{}).mockProfileResponse()
+	assert.NoError(t, err)
+	profile.Header = http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"40"}}
+	mockClient := &MockSequenceHTTPClient{responses: []*http.Response{profile}}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithRequestDelay(time.Second))
+	assert.NoError(t, err)
+
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Empty(t, clock.waited)
+	_, err = sch.QueryArticle(sampleArticleURL, &Article{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{40 * time.Second}, clock.waited)
+}
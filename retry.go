@@ -0,0 +1,98 @@
+package go_scholar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy is which failed requests are retried, how many times and how long to wait in between. A
+// Retry-After (or rate limit reset) header of a retried response takes precedence over Backoff
+type RetryPolicy struct {
+	MaxRetries   int                             // retries of a failed request
+	StatusCodes  []int                           // status codes of the responses which are retried
+	RetryOnError func(err error) bool            // whether an error of the HTTP client is retried, none if nil
+	Backoff      func(attempt int) time.Duration // wait before the retry of attempt (from 0), DefaultBackoff if nil
+}
+
+// DefaultBackoff waits 5s, 10s, 20s... (up to 2 minutes) between retries, lengthened or shortened by up to 20%
+var DefaultBackoff = ExponentialBackoff(5*time.Second, 2*time.Minute, 0.2)
+
+// DefaultRetryPolicy is the RetryPolicy of a new Scholar. It retries rate limited requests (429), gateway
+// errors and unavailable servers (502, 503 and 504) and transient network errors 3 times
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: defaultMaxRetries,
+	StatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	RetryOnError: TransientError,
+	Backoff:      DefaultBackoff,
+}
+
+// ExponentialBackoff returns a RetryPolicy.Backoff which waits base, doubled with each attempt up to maxWait, and
+// lengthened or shortened at random by up to the fraction jitter (e.g. 0.2 for ±20%) so that clients which
+// failed at the same time don't all retry at the same time
+func ExponentialBackoff(base time.Duration, maxWait time.Duration, jitter float64) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		wait := base
+		for i := 0; i < attempt && wait < maxWait; i++ {
+			wait *= 2
+		}
+		wait = min(wait, maxWait)
+		if jitter > 0 {
+			wait += time.Duration((rand.Float64()*2 - 1) * jitter * float64(wait))
+		}
+		return wait
+	}
+}
+
+// TransientError returns whether err is a network error which may not happen again: a timeout, a connection
+// which was reset, refused or closed early, or a temporary DNS failure. Errors of a context which is done
+// aren't transient
+func TransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsTemporary {
+		return true
+	}
+	for _, transient := range []error{syscall.ECONNRESET, syscall.ECONNREFUSED, syscall.ECONNABORTED,
+		syscall.EPIPE, io.EOF, io.ErrUnexpectedEOF} {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// retriesStatus returns whether responses with statusCode are retried
+func (p RetryPolicy) retriesStatus(statusCode int) bool {
+	return slices.Contains(p.StatusCodes, statusCode)
+}
+
+// retriesError returns whether an error of the HTTP client is retried
+func (p RetryPolicy) retriesError(err error) bool {
+	return p.RetryOnError != nil && p.RetryOnError(err)
+}
+
+// backoff returns the wait before the retry of attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return DefaultBackoff(attempt)
+	}
+	return p.Backoff(attempt)
+}
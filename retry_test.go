@@ -0,0 +1,135 @@
+package go_scholar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// MockFlakyHTTPClient returns its errors in order, then the responses of MockHTTPClient
+type MockFlakyHTTPClient struct {
+	errs      []error
+	callCount int
+}
+
+func (m *MockFlakyHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.callCount++
+	if len(m.errs) > 0 {
+		err := m.errs[0]
+		m.errs = m.errs[1:]
+		return nil, err
+	}
+	return (&MockHTTPClient{}).Do(req)
+}
+
+func TestTransientError(t *testing.T) {
+	urlError := func(err error) error {
+		return &url.Error{Op: "Get", URL: BaseURL, Err: err}
+	}
+	assert.True(t, TransientError(urlError(&net.OpError{Op: "read", Err: syscall.ECONNRESET})))
+	assert.True(t, TransientError(urlError(syscall.ECONNREFUSED)))
+	assert.True(t, TransientError(urlError(io.ErrUnexpectedEOF)))
+	assert.True(t, TransientError(urlError(&net.DNSError{Err: "server misbehaving", IsTemporary: true})))
+	assert.True(t, TransientError(urlError(&net.DNSError{Err: "i/o timeout", IsTimeout: true})))
+	assert.False(t, TransientError(urlError(&net.DNSError{Err: "no such host", IsNotFound: true})))
+	assert.False(t, TransientError(urlError(errors.New("unsupported protocol scheme"))))
+	assert.False(t, TransientError(urlError(fmt.Errorf("dial: %w", context.Canceled))))
+	assert.False(t, TransientError(urlError(context.DeadlineExceeded)))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 10*time.Second, 0)
+	assert.Equal(t, time.Second, backoff(0))
+	assert.Equal(t, 4*time.Second, backoff(2))
+	assert.Equal(t, 10*time.Second, backoff(4))
+	assert.Equal(t, 10*time.Second, backoff(100))
+
+	backoff = ExponentialBackoff(time.Second, time.Minute, 0.5)
+	for i := 0; i < 100; i++ {
+		wait := backoff(1)
+		assert.GreaterOrEqual(t, wait, time.Second)
+		assert.LessOrEqual(t, wait, 3*time.Second)
+	}
+}
+
+// Test that unavailable servers and transient network errors are retried with the backoff of the policy
+func TestRetryPolicy(t *testing.T) {
+	clock := &MockClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	mockClient := &MockSequenceHTTPClient{responses: []*http.Response{
+		mockHeaderResponse(503),
+		mockHeaderResponse(502),
+	}}
+	policy := DefaultRetryPolicy
+	policy.Backoff = ExponentialBackoff(time.Second, time.Minute, 0)
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithRequestDelay(0), WithRetryPolicy(policy))
+	assert.NoError(t, err)
+
+	info, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Jason Ernst", info.Name)
+	assert.Equal(t, 3, mockClient.callCount)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.waited)
+
+	// a connection reset is retried instead of failing the whole profile query
+	reset := &url.Error{Op: "Get", URL: BaseURL, Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}
+	flakyClient := &MockFlakyHTTPClient{errs: []error{reset, reset}}
+	sch, err = NewWithOptions(WithHTTPClient(flakyClient), WithClock(clock), WithRequestDelay(0), WithRetryPolicy(policy))
+	assert.NoError(t, err)
+	articles, err := sch.QueryProfileDumpResponse("SbUmSEAAAAAJ", false, 1, false)
+	assert.NoError(t, err)
+	assert.Len(t, articles, 1)
+	assert.Equal(t, 3, flakyClient.callCount)
+}
+
+// Test that a request which keeps failing reports the last failure once the retries are exhausted
+func TestRetryPolicyExhausted(t *testing.T) {
+	clock := &MockClock{}
+	mockClient := &MockSequenceHTTPClient{responses: []*http.Response{
+		mockHeaderResponse(503, "Retry-After", "1"),
+		mockHeaderResponse(503, "Retry-After", "1"),
+		mockHeaderResponse(503, "Retry-After", "1"),
+	}}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithMaxRetries(2))
+	assert.NoError(t, err)
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	assert.Equal(t, 3, mockClient.callCount)
+
+	// errors which aren't transient, and status codes which aren't in the policy, aren't retried
+	flakyClient := &MockFlakyHTTPClient{errs: []error{errors.New("unsupported protocol scheme")}}
+	sch, err = NewWithOptions(WithHTTPClient(flakyClient), WithClock(clock))
+	assert.NoError(t, err)
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.Error(t, err)
+	assert.Equal(t, 1, flakyClient.callCount)
+
+	mockClient = &MockSequenceHTTPClient{responses: []*http.Response{mockHeaderResponse(503)}}
+	sch, err = NewWithOptions(WithHTTPClient(mockClient), WithClock(clock), WithRetryPolicy(RetryPolicy{MaxRetries: 3}))
+	assert.NoError(t, err)
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, 1, mockClient.callCount)
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	_, err := NewWithOptions(WithRetryPolicy(RetryPolicy{MaxRetries: -1}))
+	assert.Error(t, err)
+	_, err = NewWithOptions(WithRetryPolicy(RetryPolicy{StatusCodes: []int{1503}}))
+	assert.Error(t, err)
+
+	sch, err := NewWithOptions(WithRetryPolicy(DefaultRetryPolicy), WithMaxRetries(5))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, sch.retryPolicy.MaxRetries)
+	assert.Equal(t, DefaultRetryPolicy.StatusCodes, sch.retryPolicy.StatusCodes)
+	assert.Equal(t, defaultMaxRetries, DefaultRetryPolicy.MaxRetries)
+}
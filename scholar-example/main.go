@@ -55,7 +55,10 @@ func main() {
 		fmt.Println(article)
 	}
 
-	sch.SaveCache("profile.json", "articles.json")
+	err = sch.SaveCache("profile.json", "articles.json")
+	if err != nil {
+		fmt.Println(err)
+	}
 	sch2 := scholar.New("profile.json", "articles.json")
 	cachedArticles2, err := sch2.QueryProfileWithMemoryCache(user, limit)
 	if err != nil {
@@ -2,14 +2,15 @@ package go_scholar
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"io"
+	"log/slog"
 	"net/http"
-	"os"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -21,6 +22,9 @@ type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// Version is the version of the library, recorded in the cache files it writes
+const Version = "0.1.0"
+
 const BaseURL = "https://scholar.google.com"
 const AGENT = "Mozilla/5.0 (X11; Linux x86_64; rv:60.0) Gecko/20100101 Firefox/81.0"
 const MAX_TIME_PROFILE = time.Second * 3600 * 24 * 7  // 1 week
@@ -30,6 +34,8 @@ type Article struct {
 	Title               string
 	Authors             string
 	ScholarURL          string
+	URL                 string // link to the publisher's page, only set for results from the results pages (e.g. Search)
+	ClusterID           string // id of the cluster of versions of the article, used by the cited by and versions pages
 	Year                int
 	Month               int
 	Day                 int
@@ -44,194 +50,309 @@ type Article struct {
 	ScholarCitedByURLs  []string
 	ScholarVersionsURLs []string
 	ScholarRelatedURLs  []string
+	CitationsByYear     map[int]int // number of citations per year, years without citations are omitted
 	LastRetrieved       time.Time
 }
 
 type Profile struct {
-	User          string
-	LastRetrieved time.Time
-	Articles      []string // list of article URLs - we'd still need to look them up in the article map
+	User            string
+	Name            string
+	Affiliation     string
+	EmailDomain     string   // domain of the verified email address, e.g. "uoguelph.ca"
+	Interests       []string // research interests listed on the profile
+	HomepageURL     string
+	PhotoURL        string
+	Metrics         ProfileMetrics
+	CitationsByYear map[int]int // number of citations per year, years without citations are omitted
+	CoAuthors       []CoAuthor
+	PublicAccess    PublicAccess
+	LastRetrieved   time.Time
+	Articles        []string // list of article URLs - we'd still need to look them up in the article map
+}
+
+// ProfileMetrics are the citation metrics from the sidebar of a profile. The *Since fields are the "recent"
+// column of the table, which only counts citations made since SinceYear (the last 5 years)
+type ProfileMetrics struct {
+	Citations      int
+	CitationsSince int
+	HIndex         int
+	HIndexSince    int
+	I10Index       int
+	I10IndexSince  int
+	SinceYear      int
+}
+
+// CoAuthor is an entry of the co-authors panel of a profile. UserID can be passed to QueryProfile
+type CoAuthor struct {
+	Name        string
+	Affiliation string
+	UserID      string
+}
+
+// PublicAccess is the number of articles of a profile which are subject to funder public access mandates,
+// split by whether they are publicly available (see QueryPublicAccess for the list of articles)
+type PublicAccess struct {
+	Available    int
+	NotAvailable int
+}
+
+// MandateArticle is an article listed on the public access page of a profile
+type MandateArticle struct {
+	Title      string
+	ScholarURL string
+	Available  bool     // whether a version of the article is publicly available
+	Mandates   []string // funding agencies whose mandates apply to the article
 }
 
 type Scholar struct {
-	articles      sync.Map      // map of articles by URL
-	profile       sync.Map      // map of profile by User string
-	httpClient    HTTPClient    // HTTP client for making requests
-	rateLimiter   *time.Ticker  // rate limiter for throttling requests
-	requestDelay  time.Duration // delay between requests
-	lastRequest   time.Time     // timestamp of last request
-	requestMutex  sync.Mutex    // mutex to synchronize requests
+	store        Store             // profile and article caches
+	cacheTTL     CacheTTL          // how long cached entries are served
+	logger       *slog.Logger      // logger of cache hits and misses, retries and errors
+	httpClient   HTTPClient        // HTTP client for making requests
+	baseURL      string            // scheme and host requests are made to, BaseURL by default
+	userAgent    string            // User-Agent header of requests, AGENT by default
+	retryPolicy  RetryPolicy       // which failed requests are retried, and how
+	maxRetryWait time.Duration     // longest Retry-After waited out before retrying
+	clock        Clock             // source of the time, and of the waits between requests
+	rateLimiter  *time.Ticker      // rate limiter for throttling requests
+	requestDelay time.Duration     // delay between requests
+	throttle     *AdaptiveThrottle // adapts requestDelay to pushback, if set
+	successes    int               // successful requests since requestDelay was last adapted
+	lastRequest  time.Time         // timestamp of last request
+	nextAllowed  time.Time         // no requests are made before this time, set from Retry-After and rate limit headers
+	requestMutex sync.Mutex        // mutex to synchronize requests, and the deprecated setters
+	immutable    bool              // created by NewWithOptions, so the deprecated setters are ignored
 }
 
+// New returns a Scholar whose profile and article caches are loaded from (and may be saved back to with
+// SaveCache) the JSON files profileCache and articleCache, and its listing cache from the file next to
+// articleCache. Missing or unreadable files start an empty cache
 func New(profileCache string, articleCache string) *Scholar {
+	store, err := NewJSONFileStore(profileCache, articleCache)
+	sch := NewWithStore(store)
+	if err != nil {
+		sch.logger.Warn("Creating new cache", "error", err)
+	}
+	return sch
+}
+
+// NewWithStore returns a Scholar which keeps its profile and article caches in store
+func NewWithStore(store Store) *Scholar {
 	// Initialize the base Scholar struct with default HTTP client and rate limiter
 	// Default to 2 seconds between requests to be conservative with Google Scholar's rate limits
 	requestDelay := 2 * time.Second
 	sch := Scholar{
-		httpClient:   &http.Client{
+		store:    store,
+		cacheTTL: DefaultCacheTTL,
+		logger:   discardLogger,
+		httpClient: &http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{},
 			},
 		},
+		baseURL:      BaseURL,
+		userAgent:    AGENT,
+		retryPolicy:  DefaultRetryPolicy,
+		maxRetryWait: defaultMaxRetryWait,
+		clock:        systemClock{},
 		requestDelay: requestDelay,
 		lastRequest:  time.Time{}, // zero time initially
 	}
-
-	profileFile, err := os.Open(profileCache)
-	if err != nil {
-		println("Error opening profile cache file: " + profileCache + " - creating new cache")
-		return &sch
-	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			println("Error closing profile cache file: " + profileCache)
-		}
-	}(profileFile)
-	profileDecoder := json.NewDecoder(profileFile)
-	var regularProfileMap map[string]Profile
-	err = profileDecoder.Decode(&regularProfileMap)
-	if err != nil {
-		println("Error decoding profile file: " + profileCache + " - creating new cache")
-		return &sch
-	}
-
-	articleFile, err := os.Open(articleCache)
-	if err != nil {
-		println("Error opening article cache file: " + articleCache + " - creating new cache")
-		return &sch
-	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			println("Error closing article cache file: " + articleCache)
-		}
-	}(articleFile)
-	articleDecoder := json.NewDecoder(articleFile)
-	var regularArticleMap map[string]*Article
-	err = articleDecoder.Decode(&regularArticleMap)
-	if err != nil {
-		println("Error decoding article cache file: " + articleCache + " - creating new cache")
-		return &sch
-	}
-
-	// convert the regular maps to sync maps
-	for key, value := range regularProfileMap {
-		sch.profile.Store(key, value)
-	}
-	fmt.Printf("Loaded cache into memory with %d profiles\n", len(regularProfileMap))
-	for key, value := range regularArticleMap {
-		sch.articles.Store(key, value)
-	}
-	fmt.Printf("Loaded cache into memory with %d articles\n", len(regularArticleMap))
-
 	return &sch
 }
 
-// SetHTTPClient allows setting a custom HTTP client (useful for testing)
+// LoadCache loads the profile and article caches written by SaveCache into the store, along with the
+// listing cache next to articleCache (see SaveCache). Nothing is loaded unless both files can be decoded
+func (sch *Scholar) LoadCache(profileCache string, articleCache string) error {
+	return loadCacheFiles(sch.store, profileCache, articleCache, listingCachePath(articleCache), sch.logger)
+}
+
+// SetHTTPClient allows setting a custom HTTP client (useful for testing). It is ignored, with a warning, by a
+// Scholar created by NewWithOptions
+//
+// Deprecated: the setters race with requests in flight, use NewWithOptions with WithHTTPClient
 func (sch *Scholar) SetHTTPClient(client HTTPClient) {
-	sch.httpClient = client
+	sch.requestMutex.Lock()
+	defer sch.requestMutex.Unlock()
+	if sch.settable("SetHTTPClient") {
+		sch.httpClient = client
+	}
 }
 
-// SetRequestDelay allows setting a custom delay between requests for throttling
+// SetRequestDelay allows setting a custom delay between requests for throttling. It is ignored, with a
+// warning, by a Scholar created by NewWithOptions
+//
+// Deprecated: the setters race with requests in flight, use NewWithOptions with WithRequestDelay
 func (sch *Scholar) SetRequestDelay(delay time.Duration) {
-	sch.requestDelay = delay
+	sch.requestMutex.Lock()
+	defer sch.requestMutex.Unlock()
+	if sch.settable("SetRequestDelay") {
+		sch.requestDelay = delay
+	}
+}
+
+// settable returns whether the deprecated setter may change the Scholar, logging a warning if it may not
+// because the Scholar was created by NewWithOptions. It is called with requestMutex held
+func (sch *Scholar) settable(setter string) bool {
+	if sch.immutable {
+		sch.logger.Warn("Ignoring setter of a Scholar created by NewWithOptions", "setter", setter)
+		return false
+	}
+	return true
 }
 
-// makeThrottledRequest makes an HTTP request with rate limiting and retry logic for 429 errors
+// makeThrottledRequest makes an HTTP request with rate limiting and retry logic for 429 errors.
+// Waiting is aborted when the context of the request is done
 func (sch *Scholar) makeThrottledRequest(req *http.Request) (*http.Response, error) {
-	const maxRetries = 3
-	const baseBackoffDelay = 5 * time.Second
-	
+	ctx := req.Context()
+	policy := sch.retryPolicy
+	maxRetries := policy.MaxRetries
+
+	// a rate limited request widens the adaptive delay once, however many times it is retried
+	rateLimited := false
+	defer func() {
+		if rateLimited {
+			sch.adaptDelay(true)
+		}
+	}()
+
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		// Apply rate limiting
+		// Apply rate limiting, and wait out any Retry-After of previous responses
 		sch.requestMutex.Lock()
-		if !sch.lastRequest.IsZero() {
-			elapsed := time.Since(sch.lastRequest)
-			if elapsed < sch.requestDelay {
-				sleepTime := sch.requestDelay - elapsed
-				sch.requestMutex.Unlock()
-				time.Sleep(sleepTime)
-				sch.requestMutex.Lock()
+		next := sch.nextAllowed
+		if !sch.lastRequest.IsZero() && sch.lastRequest.Add(sch.requestDelay).After(next) {
+			next = sch.lastRequest.Add(sch.requestDelay)
+		}
+		if sleepTime := next.Sub(sch.clock.Now()); sleepTime > 0 {
+			sch.requestMutex.Unlock()
+			if err := sch.sleep(ctx, sleepTime); err != nil {
+				return nil, err
 			}
+			sch.requestMutex.Lock()
 		}
-		sch.lastRequest = time.Now()
+		sch.lastRequest = sch.clock.Now()
+		client := sch.httpClient
 		sch.requestMutex.Unlock()
-		
-		// Make the request
-		resp, err := sch.httpClient.Do(req)
+
+		// Make the request, retrying transient network errors
+		resp, err := client.Do(req)
 		if err != nil {
-			return nil, err
+			if attempt == maxRetries || ctx.Err() != nil || !policy.retriesError(err) {
+				return nil, err
+			}
+			wait := policy.backoff(attempt)
+			sch.logger.Warn("Request failed, retrying", "url", req.URL.String(), "error", err, "attempt", attempt+1, "max_retries", maxRetries, "delay", wait)
+			sch.delayRequestsUntil(sch.clock.Now().Add(wait))
+			continue
 		}
-		
-		// If not a rate limit error, return the response
-		if resp.StatusCode != 429 {
+		wait, hasWait := retryAfter(resp, sch.clock.Now())
+		if resp.StatusCode == http.StatusTooManyRequests {
+			rateLimited = true
+		}
+
+		// If the status code isn't retried, return the response
+		if !policy.retriesStatus(resp.StatusCode) {
+			// no requests remain in the rate limit window, hold back the next ones until it is reset
+			if hasWait {
+				sch.delayRequestsUntil(sch.clock.Now().Add(min(wait, sch.maxRetryWait)))
+			}
 			return resp, nil
 		}
-		
-		// Handle 429 (Too Many Requests) with exponential backoff
+
+		// Wait as long as the response asks, or else as long as the backoff of the policy
+		if !hasWait {
+			wait = policy.backoff(attempt)
+		}
+		nextAllowed := sch.clock.Now().Add(wait)
+		if attempt == maxRetries || (hasWait && wait > sch.maxRetryWait) {
+			if resp.StatusCode != http.StatusTooManyRequests {
+				// the status code is reported by the caller
+				return resp, nil
+			}
+			resp.Body.Close()
+			// hold back the next requests as well, rather than have them rate limited too
+			sch.delayRequestsUntil(sch.clock.Now().Add(min(wait, sch.maxRetryWait)))
+			return nil, &RateLimitError{
+				URL:         req.URL.String(),
+				Attempts:    attempt + 1,
+				RetryAfter:  wait,
+				NextAllowed: nextAllowed,
+				Remaining:   rateLimitRemaining(resp.Header),
+			}
+		}
 		resp.Body.Close() // Close the response body before retrying
-		
-		if attempt == maxRetries {
-			return nil, fmt.Errorf("max retries (%d) exceeded due to rate limiting (HTTP 429)", maxRetries)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			sch.logger.Warn("Rate limited (429), retrying", "url", req.URL.String(), "attempt", attempt+1, "max_retries", maxRetries, "delay", wait)
+		} else {
+			sch.logger.Warn("Server error, retrying", "url", req.URL.String(), "status", resp.StatusCode, "attempt", attempt+1, "max_retries", maxRetries, "delay", wait)
 		}
-		
-		// Exponential backoff: baseDelay * 2^attempt
-		backoffDelay := baseBackoffDelay * time.Duration(1<<uint(attempt))
-		fmt.Printf("Rate limited (429), retrying in %v (attempt %d/%d)\n", backoffDelay, attempt+1, maxRetries)
-		time.Sleep(backoffDelay)
+		// the wait is applied by the rate limiting of the next attempt
+		sch.delayRequestsUntil(nextAllowed)
 	}
-	
+
 	return nil, fmt.Errorf("unexpected error in retry logic")
 }
 
-func (sch *Scholar) SaveCache(profileCache string, articleCache string) {
-	profileFile, err := os.Create(profileCache)
+// fetchDocument requests the page at requestURL through makeThrottledRequest and parses it. Besides
+// unexpected status codes, it returns a *BlockedError when Google Scholar serves a CAPTCHA, unusual traffic
+// or consent page in place of the requested page, so that it isn't parsed as a page without any results.
+// if dumpResponse is true, it will log the response at the debug level (useful for debugging)
+func (sch *Scholar) fetchDocument(ctx context.Context, requestURL string, dumpResponse bool) (*goquery.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
-		println("Error opening profile cache file: " + profileCache)
-		return
+		return nil, err
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			println("Error closing profile cache file: " + profileCache)
-		}
-	}(profileFile)
-	profileEncoder := json.NewEncoder(profileFile)
-	regularProfileMap := make(map[string]interface{})
-	sch.profile.Range(func(key, value interface{}) bool {
-		regularProfileMap[key.(string)] = value
-		return true
-	})
-	err = profileEncoder.Encode(regularProfileMap)
+	req.Header.Set("User-Agent", sch.userAgent)
+
+	resp, err := sch.makeThrottledRequest(req)
 	if err != nil {
-		println("Error encoding profile cache file: " + profileCache)
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	articleFile, err := os.Create(articleCache)
+	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		println("Error opening article cache file: " + articleCache)
-		return
+		return nil, err
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			println("Error closing profile cache file: " + articleCache)
-		}
-	}(articleFile)
-	articleEncoder := json.NewEncoder(articleFile)
-	regularArticleMap := make(map[string]interface{})
-	sch.articles.Range(func(key, value interface{}) bool {
-		regularArticleMap[key.(string)] = value
-		return true
-	})
-	err = articleEncoder.Encode(regularArticleMap)
-	if err != nil {
-		println("Error encoding cache file: " + articleCache)
+	if dumpResponse {
+		sch.logger.Debug("Got page", "url", requestURL, "body", string(bodyBytes))
 	}
+
+	// block pages may come with a 200, a redirect or an error status code
+	if err := detectBlockPage(resp, bodyBytes, requestURL); err != nil {
+		var blockedErr *BlockedError
+		if errors.As(err, &blockedErr) && blockedErr.Reason != "consent" {
+			sch.adaptDelay(true)
+		}
+		return nil, err
+	}
+	if err := checkStatus(resp, requestURL); err != nil {
+		return nil, err
+	}
+	sch.adaptDelay(false)
+	return goquery.NewDocumentFromReader(bytes.NewReader(bodyBytes))
+}
+
+// sleep pauses for the duration d on the clock, returning early with the context's error if it is done first
+func (sch *Scholar) sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-sch.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SaveCache writes the profile and article caches to disk so they can be loaded by New or LoadCache. The
+// listings of results pages (searches, cited by, versions and related articles) are written next to
+// articleCache, e.g. to articles.listings.json for articles.json
+func (sch *Scholar) SaveCache(profileCache string, articleCache string) error {
+	listingCache := listingCachePath(articleCache)
+	err := saveCacheFiles(sch.store, profileCache, articleCache, listingCache, sch.logger)
 	if err == nil {
-		println("Saved cache")
+		sch.logger.Info("Saved cache", "profile_cache", profileCache, "article_cache", articleCache, "listing_cache", listingCache)
 	}
+	return err
 }
 
 func (a Article) String() string {
@@ -239,42 +360,46 @@ func (a Article) String() string {
 }
 
 func (sch *Scholar) QueryProfile(user string, limit int) ([]*Article, error) {
-	return sch.QueryProfileDumpResponse(user, true, limit, false)
+	return sch.QueryProfileContext(context.Background(), user, limit)
+}
+
+// QueryProfileContext is QueryProfile with a context which can cancel the requests and the waits between them
+func (sch *Scholar) QueryProfileContext(ctx context.Context, user string, limit int) ([]*Article, error) {
+	return sch.QueryProfileDumpResponseContext(ctx, user, true, limit, false)
 }
 
 // loadCachedArticles returns articles from the article cache for a given profile.
 // Articles that fail to refresh (e.g. due to throttling) are served stale.
-func (sch *Scholar) loadCachedArticles(profile Profile) []*Article {
+func (sch *Scholar) loadCachedArticles(ctx context.Context, profile Profile) []*Article {
 	articles := make([]*Article, 0)
 	for _, articleURL := range profile.Articles {
-		articleResult, articleOk := sch.articles.Load(articleURL)
+		cacheArticle, articleOk := sch.cachedArticle(articleURL)
 		if articleOk {
-			cacheArticle := articleResult.(*Article)
-			if (time.Now().Sub(cacheArticle.LastRetrieved)).Seconds() > MAX_TIME_ARTICLE.Seconds() {
-				println("Cache expired for article: " + articleURL + "\nLast Retrieved: " + cacheArticle.LastRetrieved.String() + "\nDifference: " + time.Now().Sub(cacheArticle.LastRetrieved).String())
-				article, err := sch.QueryArticle(articleURL, &Article{}, false)
+			if sch.expired(sch.cacheTTL.Article, articleURL, cacheArticle.LastRetrieved) {
+				sch.logger.Debug("Cache expired for article", "url", articleURL, "last_retrieved", cacheArticle.LastRetrieved, "age", sch.clock.Now().Sub(cacheArticle.LastRetrieved))
+				article, err := sch.QueryArticleContext(ctx, articleURL, &Article{}, false)
 				if err == nil {
-					sch.articles.Store(articleURL, article)
+					sch.cacheArticle(article)
 					articles = append(articles, article)
-				} else {
+				} else if ctx.Err() == nil {
 					// Article refresh failed — serve stale cached version
 					// Update LastRetrieved to avoid retrying on every call
 					stale := *cacheArticle
-					stale.LastRetrieved = time.Now()
-					sch.articles.Store(articleURL, &stale)
+					stale.LastRetrieved = sch.clock.Now()
+					sch.cacheArticle(&stale)
 					articles = append(articles, &stale)
 				}
 			} else {
-				println("Cache hit for article: " + articleURL)
+				sch.logger.Debug("Cache hit for article", "url", articleURL)
 				articles = append(articles, cacheArticle)
 			}
 		} else {
 			// cache miss, query the article
-			println("Cache miss for article: " + articleURL)
-			article, err := sch.QueryArticle(articleURL, &Article{}, false)
+			sch.logger.Debug("Cache miss for article", "url", articleURL)
+			article, err := sch.QueryArticleContext(ctx, articleURL, &Article{}, false)
 			if err == nil {
 				articles = append(articles, article)
-				sch.articles.Store(articleURL, article)
+				sch.cacheArticle(article)
 			}
 		}
 	}
@@ -282,64 +407,80 @@ func (sch *Scholar) loadCachedArticles(profile Profile) []*Article {
 }
 
 func (sch *Scholar) QueryProfileWithMemoryCache(user string, limit int) ([]*Article, error) {
+	return sch.QueryProfileWithMemoryCacheContext(context.Background(), user, limit)
+}
+
+// QueryProfileWithMemoryCacheContext is QueryProfileWithMemoryCache with a context which can cancel the
+// requests and the waits between them. Stale cached data is not served when the context is done
+func (sch *Scholar) QueryProfileWithMemoryCacheContext(ctx context.Context, user string, limit int) ([]*Article, error) {
 
-	profileResult, profileOk := sch.profile.Load(user)
+	profile, profileOk := sch.cachedProfile(user)
 	if profileOk {
-		profile := profileResult.(Profile)
-		lastAccess := profile.LastRetrieved
-		if (time.Now().Sub(lastAccess)).Seconds() > MAX_TIME_PROFILE.Seconds() {
-			println("Profile cache expired for User: " + user)
+		if sch.expired(sch.cacheTTL.Profile, user, profile.LastRetrieved) {
+			sch.logger.Debug("Profile cache expired", "user", user, "last_retrieved", profile.LastRetrieved)
 			// Only fetch the profile page (queryArticles=false) to get the
 			// updated article list. Article details are served from cache
 			// via loadCachedArticles, which refreshes only expired entries.
-			profileArticles, err := sch.QueryProfileDumpResponse(user, false, limit, false)
+			info, profileArticles, err := sch.queryProfile(ctx, user, false, limit, false)
 			if err == nil {
 				var articleList []string
 				for _, article := range profileArticles {
 					articleList = append(articleList, article.ScholarURL)
 					// Update citation counts from the profile page into cached articles
-					if existing, ok := sch.articles.Load(article.ScholarURL); ok {
-						updated := *existing.(*Article)
+					if existing, ok := sch.cachedArticle(article.ScholarURL); ok {
+						updated := *existing
 						updated.NumCitations = article.NumCitations
-						sch.articles.Store(article.ScholarURL, &updated)
+						sch.cacheArticle(&updated)
 					}
 				}
-				newProfile := Profile{User: user, LastRetrieved: time.Now(), Articles: articleList}
-				sch.profile.Delete(user)
-				sch.profile.Store(user, newProfile)
-				return sch.loadCachedArticles(newProfile), nil
+				newProfile := *info
+				newProfile.LastRetrieved = sch.clock.Now()
+				newProfile.Articles = articleList
+				sch.cacheProfile(newProfile)
+				articles := sch.loadCachedArticles(ctx, newProfile)
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				return articles, nil
+			} else if ctx.Err() != nil {
+				return nil, ctx.Err()
 			} else {
 				// Refresh failed (e.g. throttled) — fall back to stale cached data.
 				// Update LastRetrieved to avoid retrying on every call.
-				fmt.Printf("Profile refresh failed for %s: %v — serving stale cache\n", user, err)
-				profile.LastRetrieved = time.Now()
-				sch.profile.Store(user, profile)
-				cached := sch.loadCachedArticles(profile)
+				sch.logger.Warn("Profile refresh failed, serving stale cache", "user", user, "error", err)
+				profile.LastRetrieved = sch.clock.Now()
+				sch.cacheProfile(profile)
+				cached := sch.loadCachedArticles(ctx, profile)
 				if len(cached) > 0 {
 					return cached, nil
 				}
 				return nil, err
 			}
 		} else {
-			println("Profile cache hit for User: " + user)
-			return sch.loadCachedArticles(profile), nil
+			sch.logger.Debug("Profile cache hit", "user", user)
+			articles := sch.loadCachedArticles(ctx, profile)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return articles, nil
 		}
 	} else {
-		println("Profile cache miss for User: " + user)
-		articles, err := sch.QueryProfileDumpResponse(user, true, limit, false)
+		sch.logger.Debug("Profile cache miss", "user", user)
+		info, articles, err := sch.queryProfile(ctx, user, true, limit, false)
 		if err == nil {
 			var articleList []string
 			for _, article := range articles {
 				articleList = append(articleList, article.ScholarURL)
 			}
-			newProfile := Profile{User: user, LastRetrieved: time.Now(), Articles: articleList}
-			sch.profile.Store(user, newProfile)
+			newProfile := *info
+			newProfile.LastRetrieved = sch.clock.Now()
+			newProfile.Articles = articleList
+			sch.cacheProfile(newProfile)
 			return articles, nil
 		} else {
 			return nil, err
 		}
 	}
-	return nil, errors.New("Shouldn't have got here")
 }
 
 // QueryProfileDumpResponse queries the profile of a User and returns a list of Articles
@@ -348,10 +489,68 @@ func (sch *Scholar) QueryProfileWithMemoryCache(user string, limit int) ([]*Arti
 //	we may wish to set this to false if we are only interested in some article info, or we have a cache hit and we just
 //	want to get updated information from the profile page only to save requests
 //
-// if dumpResponse is true, it will print the response to stdout (useful for debugging)
+// if dumpResponse is true, it will log the response at the debug level (useful for debugging)
 func (sch *Scholar) QueryProfileDumpResponse(user string, queryArticles bool, limit int, dumpResponse bool) ([]*Article, error) {
+	return sch.QueryProfileDumpResponseContext(context.Background(), user, queryArticles, limit, dumpResponse)
+}
+
+// QueryProfileDumpResponseContext is QueryProfileDumpResponse with a context which can cancel the requests
+// and the waits between them
+func (sch *Scholar) QueryProfileDumpResponseContext(ctx context.Context, user string, queryArticles bool, limit int, dumpResponse bool) ([]*Article, error) {
+	_, articles, err := sch.queryProfile(ctx, user, queryArticles, limit, dumpResponse)
+	return articles, err
+}
+
+// QueryProfileInfo returns the header information of a User's profile (name, affiliation, interests, etc.).
+// A cached profile which hasn't expired is returned without making any requests, otherwise only the
+// first profile page is fetched and the header of any cached profile is refreshed.
+func (sch *Scholar) QueryProfileInfo(user string) (*Profile, error) {
+	return sch.QueryProfileInfoContext(context.Background(), user)
+}
+
+// QueryProfileInfoContext is QueryProfileInfo with a context which can cancel the request
+func (sch *Scholar) QueryProfileInfoContext(ctx context.Context, user string) (*Profile, error) {
+	profile, profileOk := sch.cachedProfile(user)
+	if profileOk {
+		if profile.Name != "" && !sch.expired(sch.cacheTTL.Profile, user, profile.LastRetrieved) {
+			sch.logger.Debug("Profile cache hit", "user", user)
+			return &profile, nil
+		}
+	}
+
+	info, _, err := sch.fetchProfilePage(ctx, user, 0, 20, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if profileOk {
+		// only the header is refreshed here, the article list and its timestamp are left alone so that
+		// QueryProfileWithMemoryCache still refreshes the articles when they expire
+		profile.setInfo(info)
+		sch.cacheProfile(profile)
+	}
+	return info, nil
+}
+
+// setInfo copies the header information of info into the profile
+func (p *Profile) setInfo(info *Profile) {
+	p.Name = info.Name
+	p.Affiliation = info.Affiliation
+	p.EmailDomain = info.EmailDomain
+	p.Interests = info.Interests
+	p.HomepageURL = info.HomepageURL
+	p.PhotoURL = info.PhotoURL
+	p.Metrics = info.Metrics
+	p.CitationsByYear = info.CitationsByYear
+	p.CoAuthors = info.CoAuthors
+	p.PublicAccess = info.PublicAccess
+}
+
+// queryProfile queries the pages of a User's profile, returning the profile header parsed from the
+// first page along with up to limit Articles (see QueryProfileDumpResponse)
+func (sch *Scholar) queryProfile(ctx context.Context, user string, queryArticles bool, limit int, dumpResponse bool) (*Profile, []*Article, error) {
+	var info *Profile
 	var articles []*Article
-	
+
 	// Use a reasonable page size for each request, but not too large to avoid timeouts
 	// Google Scholar typically works with pagesize 20-100
 	pageSize := 80
@@ -361,80 +560,65 @@ func (sch *Scholar) QueryProfileDumpResponse(user string, queryArticles bool, li
 	if pageSize < 20 {
 		pageSize = 20 // Google Scholar typically has a minimum page size
 	}
-	
+
 	cstart := 0
 	remainingArticles := limit
-	
+
 	for remainingArticles > 0 {
 		// Fetch a page of articles
-		pageArticles, err := sch.fetchProfilePage(user, cstart, pageSize, queryArticles, dumpResponse)
+		pageInfo, pageArticles, err := sch.fetchProfilePage(ctx, user, cstart, pageSize, queryArticles, dumpResponse)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if info == nil {
+			info = pageInfo
 		}
-		
+
 		// If no articles returned, we've reached the end
 		if len(pageArticles) == 0 {
 			break
 		}
-		
+
 		// Add articles up to our limit
 		articlesToAdd := remainingArticles
 		if len(pageArticles) < articlesToAdd {
 			articlesToAdd = len(pageArticles)
 		}
-		
+
 		articles = append(articles, pageArticles[:articlesToAdd]...)
 		remainingArticles -= articlesToAdd
-		
+
 		// If we got fewer articles than requested pagesize, we've reached the end
 		if len(pageArticles) < pageSize {
 			break
 		}
-		
+
 		// Move to next page
 		cstart += pageSize
 	}
+	if info == nil {
+		info = &Profile{User: user}
+	}
 
-	return articles, nil
+	return info, articles, nil
 }
 
-// fetchProfilePage fetches a single page of articles from Google Scholar
-func (sch *Scholar) fetchProfilePage(user string, cstart, pageSize int, queryArticles bool, dumpResponse bool) ([]*Article, error) {
+// fetchProfilePage fetches a single page of articles from Google Scholar, along with the profile
+// header information which is repeated at the top of every page
+func (sch *Scholar) fetchProfilePage(ctx context.Context, user string, cstart, pageSize int, queryArticles bool, dumpResponse bool) (*Profile, []*Article, error) {
 	var articles []*Article
-	
-	requestURL := BaseURL + "/citations?user=" + user + "&cstart=" + strconv.Itoa(cstart) + "&pagesize=" + strconv.Itoa(pageSize)
-	req, err := http.NewRequest("GET", requestURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", AGENT)
-	
-	resp, err := sch.makeThrottledRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != 200 {
-		rateLimitRemaining := resp.Header.Get("x-ratelimit-remaining")
-		errorString := fmt.Sprintf("Scholar: HTTP Status Code from URL: %s %d %s rate limit remaining?: %s", requestURL, resp.StatusCode, resp.Status, rateLimitRemaining)
-		return nil, errors.New(errorString)
-	}
 
-	if dumpResponse {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-		// Reset body for subsequent parsing
-		resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		println("GOT AUTHOR PAGE (cstart=" + strconv.Itoa(cstart) + "): \n", string(bodyBytes))
+	requestURL := sch.baseURL + "/citations?user=" + user + "&cstart=" + strconv.Itoa(cstart) + "&pagesize=" + strconv.Itoa(pageSize)
+	doc, err := sch.fetchDocument(ctx, requestURL, dumpResponse)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, err
+	// the articles table is present even when there are no (more) articles
+	if doc.Find("#gsc_a_b").Length() == 0 {
+		return nil, nil, fmt.Errorf("%w: no articles table on profile page %s", ErrLayoutChanged, requestURL)
 	}
+	info := parseProfileInfo(doc, user, sch.baseURL)
 
 	// Process articles from this page
 	doc.Find(".gsc_a_tr").Each(func(i int, s *goquery.Selection) {
@@ -444,80 +628,195 @@ func (sch *Scholar) fetchProfilePage(user string, cstart, pageSize int, queryArt
 		article.Title = link.Text()
 
 		tempURL, _ := link.Attr("href")
-		article.ScholarURL = BaseURL + tempURL
+		article.ScholarURL = sch.baseURL + tempURL
 		article.Year, _ = strconv.Atoi(s.Find(".gsc_a_y").Find("span").Text())
 		article.NumCitations, _ = strconv.Atoi(s.Find(".gsc_a_c").Children().First().Text())
 
 		if queryArticles {
-			articleResult, articleOk := sch.articles.Load(BaseURL + tempURL)
+			cacheArticle, articleOk := sch.cachedArticle(sch.baseURL + tempURL)
 			if articleOk {
 				// hit the cache
-				cacheArticle := articleResult.(*Article)
-				if (time.Now().Sub(article.LastRetrieved)).Seconds() > MAX_TIME_ARTICLE.Seconds() {
-					println("Cache expired for article" + BaseURL + tempURL + "\nLast Retrieved: " + cacheArticle.LastRetrieved.String() + "\nDifference: " + time.Now().Sub(cacheArticle.LastRetrieved).String())
+				if sch.expired(sch.cacheTTL.Article, sch.baseURL+tempURL, cacheArticle.LastRetrieved) {
+					sch.logger.Debug("Cache expired for article", "url", sch.baseURL+tempURL, "last_retrieved", cacheArticle.LastRetrieved, "age", sch.clock.Now().Sub(cacheArticle.LastRetrieved))
 					// expired cache entry, replace it
-					article, err = sch.QueryArticle(BaseURL+tempURL, article, dumpResponse)
+					refreshed, err := sch.QueryArticleContext(ctx, sch.baseURL+tempURL, article, dumpResponse)
 					if err == nil {
-						// only delete and store if we were successful
-						sch.articles.Delete(BaseURL + tempURL)
-						sch.articles.Store(BaseURL+tempURL, article)
+						// only store if we were successful
+						article = refreshed
+						sch.cacheArticle(article)
+					} else {
+						// serve the stale entry with the citations from the profile page
+						cacheArticle.NumCitations = article.NumCitations
+						article = cacheArticle
 					}
 				} else {
-					println("Cache hit for article" + BaseURL + tempURL)
+					sch.logger.Debug("Cache hit for article", "url", sch.baseURL+tempURL)
 					// not expired, update any new information
 					cacheArticle.NumCitations = article.NumCitations // update the citations since thats all that might change
 					article = cacheArticle
-					sch.articles.Store(BaseURL+tempURL, article)
+					sch.cacheArticle(article)
 				}
 			} else {
-				println("Cache miss for article" + BaseURL + tempURL)
-				article, err = sch.QueryArticle(BaseURL+tempURL, article, dumpResponse)
+				sch.logger.Debug("Cache miss for article", "url", sch.baseURL+tempURL)
+				queried, err := sch.QueryArticleContext(ctx, sch.baseURL+tempURL, article, dumpResponse)
 				if err == nil {
-					sch.articles.Store(BaseURL+tempURL, article)
+					article = queried
+					sch.cacheArticle(article)
 				}
 			}
 		}
 		articles = append(articles, article)
 	})
+	// article queries which failed because the context is done must not be mistaken for a complete page
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 
-	return articles, nil
+	return info, articles, nil
 }
 
-func (sch *Scholar) QueryArticle(url string, article *Article, dumpResponse bool) (*Article, error) {
-	article.ScholarURL = url
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+// parseProfileInfo parses the header of a profile page (#gsc_prf)
+func parseProfileInfo(doc *goquery.Document, user string, baseURL string) *Profile {
+	profile := &Profile{User: user}
+	header := doc.Find("#gsc_prf")
+	profile.Name = strings.TrimSpace(header.Find("#gsc_prf_in").Text())
+	// the first line without an id is the affiliation, the others are the verified email and interests
+	profile.Affiliation = strings.TrimSpace(header.Find(".gsc_prf_il").Not("#gsc_prf_ivh, #gsc_prf_int").First().Text())
+
+	verified := header.Find("#gsc_prf_ivh")
+	emailText := strings.TrimSpace(verified.Text())
+	if strings.HasPrefix(emailText, "Verified email at ") {
+		fields := strings.Fields(strings.TrimPrefix(emailText, "Verified email at "))
+		if len(fields) > 0 {
+			profile.EmailDomain = fields[0]
+		}
 	}
-	req.Header.Set("User-Agent", AGENT)
-	
-	resp, err := sch.makeThrottledRequest(req)
-	if err != nil {
-		return nil, err
+	profile.HomepageURL, _ = verified.Find("a").Attr("href")
+
+	header.Find("#gsc_prf_int a").Each(func(i int, s *goquery.Selection) {
+		profile.Interests = append(profile.Interests, strings.TrimSpace(s.Text()))
+	})
+
+	photoURL, _ := header.Find("#gsc_prf_pup-img").Attr("src")
+	if strings.HasPrefix(photoURL, "/") {
+		photoURL = baseURL + photoURL
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != 200 {
-		errorString := fmt.Sprintf("Scholar: HTTP Status Code: %d", resp.StatusCode)
-		return nil, errors.New(errorString)
+	profile.PhotoURL = photoURL
+	profile.Metrics = parseProfileMetrics(doc)
+	profile.CitationsByYear = parseCitationHistogram(doc.Find(".gsc_md_hist_b"), ".gsc_g_t", ".gsc_g_a", ".gsc_g_al")
+	profile.CoAuthors = parseCoAuthors(doc)
+	// the first available / not available entries are the counts, the second ones are the legend
+	publicAccess := doc.Find(".gsc_rsb_m")
+	profile.PublicAccess.Available = parseLeadingInt(publicAccess.Find(".gsc_rsb_m_a").First().Text())
+	profile.PublicAccess.NotAvailable = parseLeadingInt(publicAccess.Find(".gsc_rsb_m_na").First().Text())
+	return profile
+}
+
+// parseLeadingInt parses the number at the start of text such as "1,234 articles", returning 0 if there is none
+func parseLeadingInt(text string) int {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0
 	}
+	number, _ := strconv.Atoi(strings.ReplaceAll(fields[0], ",", ""))
+	return number
+}
 
-	if dumpResponse {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
+// parseCoAuthors parses the co-authors panel (.gsc_rsb_a) of a profile page
+func parseCoAuthors(doc *goquery.Document) []CoAuthor {
+	var coAuthors []CoAuthor
+	doc.Find(".gsc_rsb_a .gsc_rsb_aa").Each(func(i int, s *goquery.Selection) {
+		desc := s.Find(".gsc_rsb_a_desc")
+		link := desc.Find("a").First()
+		coAuthor := CoAuthor{
+			Name:        strings.TrimSpace(link.Text()),
+			Affiliation: strings.TrimSpace(desc.Find(".gsc_rsb_a_ext").Not(".gsc_rsb_a_ext2").First().Text()),
+		}
+		href, _ := link.Attr("href")
+		if parsed, err := url.Parse(href); err == nil {
+			coAuthor.UserID = parsed.Query().Get("user")
+		}
+		coAuthors = append(coAuthors, coAuthor)
+	})
+	return coAuthors
+}
+
+// parseCitationHistogram parses a citations per year bar chart. Years with no citations have no bar, so
+// each bar is matched to its year by its z-index, which counts down from the number of years to 1
+func parseCitationHistogram(chart *goquery.Selection, yearSelector, barSelector, labelSelector string) map[int]int {
+	var years []int
+	chart.Find(yearSelector).Each(func(i int, s *goquery.Selection) {
+		year, err := strconv.Atoi(strings.TrimSpace(s.Text()))
+		if err == nil {
+			years = append(years, year)
 		}
-		req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		println("GOT ARTICLE: \n", string(bodyBytes))
+	})
+	if len(years) == 0 {
+		return nil
 	}
+	histogram := make(map[int]int)
+	chart.Find(barSelector).Each(func(i int, s *goquery.Selection) {
+		index := i
+		style, _ := s.Attr("style")
+		if _, zIndex, found := strings.Cut(style, "z-index:"); found {
+			zIndex, _, _ = strings.Cut(zIndex, ";")
+			z, err := strconv.Atoi(strings.TrimSpace(zIndex))
+			if err == nil {
+				index = len(years) - z
+			}
+		}
+		if index < 0 || index >= len(years) {
+			return
+		}
+		histogram[years[index]], _ = strconv.Atoi(strings.TrimSpace(s.Find(labelSelector).Text()))
+	})
+	return histogram
+}
+
+// parseProfileMetrics parses the citation metrics table (#gsc_rsb_st) of a profile page. The rows are always
+// citations, h-index and i10-index in that order, so they are matched by position rather than by their
+// (localized) labels
+func parseProfileMetrics(doc *goquery.Document) ProfileMetrics {
+	var metrics ProfileMetrics
+	table := doc.Find("#gsc_rsb_st")
+	sinceHeader := table.Find(".gsc_rsb_sth").Last().Text()
+	metrics.SinceYear, _ = strconv.Atoi(strings.TrimSpace(strings.TrimLeftFunc(sinceHeader, func(r rune) bool {
+		return r < '0' || r > '9'
+	})))
+	table.Find("tbody tr").Each(func(i int, s *goquery.Selection) {
+		cells := s.Find(".gsc_rsb_std")
+		all, _ := strconv.Atoi(strings.TrimSpace(cells.Eq(0).Text()))
+		since, _ := strconv.Atoi(strings.TrimSpace(cells.Eq(1).Text()))
+		switch i {
+		case 0:
+			metrics.Citations, metrics.CitationsSince = all, since
+		case 1:
+			metrics.HIndex, metrics.HIndexSince = all, since
+		case 2:
+			metrics.I10Index, metrics.I10IndexSince = all, since
+		}
+	})
+	return metrics
+}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+func (sch *Scholar) QueryArticle(url string, article *Article, dumpResponse bool) (*Article, error) {
+	return sch.QueryArticleContext(context.Background(), url, article, dumpResponse)
+}
+
+// QueryArticleContext is QueryArticle with a context which can cancel the request
+func (sch *Scholar) QueryArticleContext(ctx context.Context, url string, article *Article, dumpResponse bool) (*Article, error) {
+	article.ScholarURL = url
+	doc, err := sch.fetchDocument(ctx, url, dumpResponse)
 	if err != nil {
 		return nil, err
 	}
-	article.LastRetrieved = time.Now()
+	if doc.Find("#gsc_oci_title").Length() == 0 {
+		return nil, fmt.Errorf("%w: no title on article page %s", ErrLayoutChanged, url)
+	}
+	article.LastRetrieved = sch.clock.Now()
 	article.Articles = 0
 	article.PdfURL, _ = doc.Find(".gsc_oci_title_ggi").Children().First().Attr("href") // assume the link is the first child
+	article.CitationsByYear = parseCitationHistogram(doc.Find("#gsc_oci_graph_bars"), ".gsc_oci_g_t", ".gsc_oci_g_a", ".gsc_oci_g_al")
 	doc.Find(".gs_scl").Each(func(i int, s *goquery.Selection) {
 		text := s.Find(".gsc_oci_field").Text()
 		if text == "Authors" {
@@ -555,7 +854,8 @@ func (sch *Scholar) QueryArticle(url string, article *Article, dumpResponse bool
 		//		article.NumCitations, _ = strconv.Atoi(parts[1])
 		//	}
 		//}
-		if text == "Scholar Articles" {
+		// the field and link texts differ in case and wrapping between page versions
+		if strings.EqualFold(text, "Scholar Articles") {
 			article.Articles += 1
 			articles := s.Find(".gsc_oci_value")
 			articles.Find(".gsc_oci_merged_snippet").Each(func(i int, s *goquery.Selection) {
@@ -563,12 +863,15 @@ func (sch *Scholar) QueryArticle(url string, article *Article, dumpResponse bool
 				// https://scholar.google.com/citations?view_op=view_citation&hl=en&user=ECQMeb0AAAAJ&citation_for_view=ECQMeb0AAAAJ:u5HHmVD_uO8C
 				// this seems to happen if the entry is a book and there are Articles within it
 				s.Find(".gsc_oms_link").Each(func(i int, l *goquery.Selection) {
-					linkText := l.Text()
+					linkText := strings.ToLower(normalizeSpace(l.Text()))
 					linkUrl, _ := l.Attr("href")
-					if strings.Contains(linkText, "Cited by") {
+					if strings.Contains(linkText, "cited by") {
 						article.ScholarCitedByURLs = append(article.ScholarCitedByURLs, linkUrl)
+						if article.ClusterID == "" {
+							article.ClusterID = citesClusterID(linkUrl)
+						}
 					}
-					if strings.Contains(linkText, "Related Articles") {
+					if strings.Contains(linkText, "related articles") {
 						article.ScholarRelatedURLs = append(article.ScholarRelatedURLs, linkUrl)
 					}
 					if strings.Contains(linkText, "versions") {
@@ -580,3 +883,38 @@ func (sch *Scholar) QueryArticle(url string, article *Article, dumpResponse bool
 	})
 	return article, nil
 }
+
+// QueryPublicAccess queries the public access page of a User, which lists the articles subject to funder
+// mandates along with whether they are publicly available
+func (sch *Scholar) QueryPublicAccess(user string) ([]MandateArticle, error) {
+	return sch.QueryPublicAccessContext(context.Background(), user)
+}
+
+// QueryPublicAccessContext is QueryPublicAccess with a context which can cancel the request
+func (sch *Scholar) QueryPublicAccessContext(ctx context.Context, user string) ([]MandateArticle, error) {
+	requestURL := sch.baseURL + "/citations?view_op=list_mandates&hl=en&user=" + user
+	doc, err := sch.fetchDocument(ctx, requestURL, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var articles []MandateArticle
+	doc.Find(".gsc_mnd_sec").Each(func(i int, section *goquery.Selection) {
+		id, _ := section.Attr("id")
+		available := !strings.HasSuffix(id, "_na")
+		section.Find(".gsc_mnd_art").Each(func(i int, s *goquery.Selection) {
+			link := s.Find(".gsc_mnd_art_rvw")
+			article := MandateArticle{
+				Title:     strings.Join(strings.Fields(link.Text()), " "),
+				Available: available,
+			}
+			href, _ := link.Attr("href")
+			article.ScholarURL = sch.baseURL + href
+			s.Find(".gsc_mnd_art_mnd").Each(func(i int, m *goquery.Selection) {
+				article.Mandates = append(article.Mandates, strings.TrimSpace(m.Text()))
+			})
+			articles = append(articles, article)
+		})
+	})
+	return articles, nil
+}
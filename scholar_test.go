@@ -1,6 +1,7 @@
 package go_scholar
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"io"
@@ -22,17 +23,32 @@ type MockRateLimitHTTPClient struct {
 
 func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	url := req.URL.String()
-	
+
 	// Mock profile request - check if it's a profile query
 	if strings.Contains(url, "/citations?user=") && strings.Contains(url, "&cstart=") {
 		return m.mockProfileResponse()
 	}
-	
+
 	// Mock article request - check if it's an article view
 	if strings.Contains(url, "view_citation") {
 		return m.mockArticleResponse()
 	}
-	
+
+	// Mock public access request
+	if strings.Contains(url, "view_op=list_mandates") {
+		return mockFileResponse("sample_mandates_page.html")
+	}
+
+	// Mock author search request
+	if strings.Contains(url, "view_op=search_authors") {
+		return mockFileResponse("sample_authors_page.html")
+	}
+
+	// Mock results page request
+	if strings.Contains(url, "/scholar?") {
+		return mockFileResponse("sample_search_page.html")
+	}
+
 	// Default to empty response for unknown URLs
 	return &http.Response{
 		StatusCode: 404,
@@ -40,6 +56,19 @@ func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+// mockFileResponse returns a 200 response with the contents of a sample page
+func mockFileResponse(filename string) (*http.Response, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader(string(content))),
+	}, nil
+}
+
 func (m *MockHTTPClient) mockProfileResponse() (*http.Response, error) {
 	content, err := os.ReadFile("sample_author_page.html")
 	if err != nil {
@@ -252,7 +281,7 @@ func TestRequestDelayConfiguration(t *testing.T) {
 }
 
 // MockAlwaysFailHTTPClient returns 500 to simulate server failure without
-// triggering the 429 retry/backoff logic (which is tested separately in TestRateLimitRetry).
+// triggering the retry/backoff logic (500 isn't retried by DefaultRetryPolicy, see retry_test.go).
 type MockAlwaysFailHTTPClient struct{}
 
 func (m *MockAlwaysFailHTTPClient) Do(req *http.Request) (*http.Response, error) {
@@ -276,10 +305,9 @@ func TestStaleCacheFallback(t *testing.T) {
 	originalCount := len(articles)
 
 	// Expire the profile cache by storing it with an old timestamp
-	profileResult, _ := sch.profile.Load("SbUmSEAAAAAJ")
-	profile := profileResult.(Profile)
+	profile, _, _ := sch.store.LoadProfile("SbUmSEAAAAAJ")
 	profile.LastRetrieved = time.Now().Add(-8 * 24 * time.Hour) // 8 days ago (past 7-day expiry)
-	sch.profile.Store("SbUmSEAAAAAJ", profile)
+	sch.store.StoreProfile(profile)
 
 	// Now switch to a client that always fails
 	sch.SetHTTPClient(&MockAlwaysFailHTTPClient{})
@@ -310,10 +338,9 @@ func TestProfileRefreshPreservesArticles(t *testing.T) {
 	}
 
 	// Expire the profile cache so the next call triggers a profile-only refresh
-	profileResult, _ := sch.profile.Load("SbUmSEAAAAAJ")
-	profile := profileResult.(Profile)
+	profile, _, _ := sch.store.LoadProfile("SbUmSEAAAAAJ")
 	profile.LastRetrieved = time.Now().Add(-8 * 24 * time.Hour) // 8 days ago
-	sch.profile.Store("SbUmSEAAAAAJ", profile)
+	sch.store.StoreProfile(profile)
 
 	// Second query should refresh profile (queryArticles=false) and serve article details from cache
 	articles2, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 10)
@@ -346,3 +373,176 @@ func TestPaginationLogic(t *testing.T) {
 		assert.NotEmpty(t, article.Title, "Article %d should have a title", i+1)
 	}
 }
+
+// Test that the profile header is parsed from the sample author page
+func TestQueryProfileInfo(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	profile, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "SbUmSEAAAAAJ", profile.User)
+	assert.Equal(t, "Jason Ernst", profile.Name)
+	assert.Equal(t, "University of Guelph", profile.Affiliation)
+	assert.Equal(t, "uoguelph.ca", profile.EmailDomain)
+	assert.Equal(t, "http://www.jasonernst.com/", profile.HomepageURL)
+	assert.Contains(t, profile.PhotoURL, "view_photo")
+	assert.Contains(t, profile.Interests, "Wireless Mesh Networks")
+	assert.Contains(t, profile.Interests, "Robotics")
+}
+
+// Test that the cached profile carries the header information
+func TestProfileCacheHasInfo(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	_, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+
+	profile, ok, err := sch.store.LoadProfile("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Jason Ernst", profile.Name)
+	assert.Len(t, profile.Articles, 1)
+
+	// a fresh cached profile is served without making any requests
+	sch.SetHTTPClient(&MockAlwaysFailHTTPClient{})
+	info, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "University of Guelph", info.Affiliation)
+}
+
+// Test that the citation metrics table is parsed from the sample author page
+func TestProfileMetrics(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	profile, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileMetrics{
+		Citations:      1083,
+		CitationsSince: 806,
+		HIndex:         14,
+		HIndexSince:    8,
+		I10Index:       17,
+		I10IndexSince:  7,
+		SinceYear:      2019,
+	}, profile.Metrics)
+}
+
+// Test that the citations per year histogram is parsed and persisted with the profile cache
+func TestProfileCitationsByYear(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	profile, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Len(t, profile.CitationsByYear, 16)
+	assert.Equal(t, 3, profile.CitationsByYear[2009])
+	assert.Equal(t, 5, profile.CitationsByYear[2010])
+	assert.Equal(t, 35, profile.CitationsByYear[2024])
+
+	_, err = sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 1)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	profileCache := dir + "/profiles.json"
+	articleCache := dir + "/articles.json"
+	sch.SaveCache(profileCache, articleCache)
+
+	loaded := New(profileCache, articleCache)
+	loadedProfile, ok, err := loaded.store.LoadProfile("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, profile.CitationsByYear, loadedProfile.CitationsByYear)
+}
+
+// Test that the citations per year histogram is parsed from the sample article page
+func TestArticleCitationsByYear(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	article, err := sch.QueryArticle("https://scholar.google.com/citations?view_op=view_citation&hl=en&user=SbUmSEAAAAAJ", &Article{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{2019: 44, 2020: 67, 2021: 91, 2022: 114, 2023: 148, 2024: 17}, article.CitationsByYear)
+}
+
+// Test that the co-authors panel is parsed from the sample author page
+func TestProfileCoAuthors(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	profile, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Len(t, profile.CoAuthors, 16)
+	assert.Equal(t, CoAuthor{
+		Name:        "Stefan C. Kremer",
+		Affiliation: "School of Computer Science, University of Guelph",
+		UserID:      "ECQMeb0AAAAJ",
+	}, profile.CoAuthors[0])
+	for _, coAuthor := range profile.CoAuthors {
+		assert.NotEmpty(t, coAuthor.Name)
+		assert.NotEmpty(t, coAuthor.UserID)
+	}
+}
+
+// Test that the public access counts and the mandate list are parsed
+func TestPublicAccess(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	profile, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, PublicAccess{Available: 5, NotAvailable: 3}, profile.PublicAccess)
+
+	articles, err := sch.QueryPublicAccess("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Len(t, articles, 5)
+	assert.False(t, articles[0].Available)
+	assert.Equal(t, "An adaptive load balancing algorithm for heterogeneous wireless networks", articles[0].Title)
+	assert.Contains(t, articles[0].ScholarURL, "citation_for_view=SbUmSEAAAAAJ:zA6iFVUQeVQC")
+	assert.Len(t, articles[2].Mandates, 2)
+	assert.True(t, articles[3].Available)
+}
+
+// Test that the wait between requests is aborted when the context is done
+func TestContextCancelsThrottleWait(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(10 * time.Second)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	_, err := sch.QueryProfileDumpResponse("SbUmSEAAAAAJ", false, 1, false)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = sch.QueryProfileWithMemoryCacheContext(ctx, "SbUmSEAAAAAJ", 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// nothing is cached for the aborted query
+	_, ok, _ := sch.store.LoadProfile("SbUmSEAAAAAJ")
+	assert.False(t, ok)
+}
+
+// Test that the backoff after a 429 is aborted when the context is done
+func TestContextCancelsRateLimitBackoff(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockRateLimitHTTPClient{shouldReturn429: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := sch.QueryProfileDumpResponseContext(ctx, "SbUmSEAAAAAJ", false, 1, false)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
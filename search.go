@@ -0,0 +1,239 @@
+package go_scholar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MAX_TIME_SEARCH = time.Second * 3600 * 24 // 1 day
+
+// number of results Google Scholar shows on each results page
+const searchPageSize = 10
+
+// SearchQuery is a keyword search of the Google Scholar results pages (/scholar?q=). The filters map onto
+// the parameters of Google Scholar's advanced search form, and at least one of Query, ExactPhrase, Author
+// or Source must be set
+type SearchQuery struct {
+	Query            string // words to search for
+	ExactPhrase      string // phrase which must appear exactly
+	ExcludeWords     string // words which must not appear
+	Author           string // only return articles by this author, e.g. "JB Ernst"
+	Source           string // only return articles published in this source, e.g. "IEEE access"
+	YearLow          int    // only return articles published in or after this year
+	YearHigh         int    // only return articles published in or before this year
+	ExcludePatents   bool   // exclude patents from the articles searched
+	ExcludeCitations bool   // exclude citations without a link to the article ([CITATION] results)
+	CaseLaw          bool   // search case law instead of articles (ExcludePatents is ignored)
+	Limit            int    // maximum number of results to return, defaults to a single page of results
+}
+
+// Listing is a cached list of results from the Google Scholar results pages. It is keyed by the query it
+// is the results of: "search:" and the parameters of a Search, or the parameter and cluster id of the
+// links of an article, e.g. "cites:<cluster id>" for the articles citing it
+type Listing struct {
+	Key           string
+	Articles      []*Article
+	Complete      bool // whether the last page of results was reached
+	LastRetrieved time.Time
+}
+
+// values returns the URL parameters of the results page for the query. Encoding them gives the same string
+// for the same filters, so it is used as the cache key of the query
+func (q SearchQuery) values() url.Values {
+	params := url.Values{}
+	params.Set("q", q.Query)
+	params.Set("hl", "en") // the results are parsed by their English link text
+	if q.ExactPhrase != "" {
+		params.Set("as_epq", q.ExactPhrase)
+	}
+	if q.ExcludeWords != "" {
+		params.Set("as_eq", q.ExcludeWords)
+	}
+	if q.Author != "" {
+		params.Set("as_sauthors", q.Author)
+	}
+	if q.Source != "" {
+		params.Set("as_publication", q.Source)
+	}
+	if q.YearLow > 0 {
+		params.Set("as_ylo", strconv.Itoa(q.YearLow))
+	}
+	if q.YearHigh > 0 {
+		params.Set("as_yhi", strconv.Itoa(q.YearHigh))
+	}
+	// as_sdt selects what is searched: "0,5" for articles including patents, "1,5" for articles excluding
+	// patents and "2006" for case law, as sent by the links of Google Scholar's own results pages
+	switch {
+	case q.CaseLaw:
+		params.Set("as_sdt", "2006")
+	case q.ExcludePatents:
+		params.Set("as_sdt", "1,5")
+	default:
+		params.Set("as_sdt", "0,5")
+	}
+	if q.ExcludeCitations {
+		params.Set("as_vis", "1")
+	}
+	return params
+}
+
+// Search queries the Google Scholar results pages for the query and returns up to query.Limit results.
+// The results only contain the information shown on the results page, ScholarURL is not set since the
+// results link to the publishers rather than to Google Scholar (see Article.URL and Article.ClusterID)
+func (sch *Scholar) Search(ctx context.Context, query SearchQuery) ([]*Article, error) {
+	if strings.TrimSpace(query.Query+query.ExactPhrase+query.Author+query.Source) == "" {
+		return nil, errors.New("Scholar: empty search query")
+	}
+	if query.YearLow > 0 && query.YearHigh > 0 && query.YearLow > query.YearHigh {
+		return nil, fmt.Errorf("Scholar: invalid search year range %d-%d", query.YearLow, query.YearHigh)
+	}
+	params := query.values()
+	return sch.queryListing(ctx, params, pageLimit(query.Limit, searchPageSize), "search:"+params.Encode())
+}
+
+// pageLimit returns limit, or pageSize if limit <= 0. The result listings (searches, links and
+// authors) return a single page of results when they aren't given a limit
+func pageLimit(limit int, pageSize int) int {
+	if limit <= 0 {
+		return pageSize
+	}
+	return limit
+}
+
+// queryListing returns up to limit results of the results pages with the given parameters, paging through
+// the results as needed. Listings are cached in the store by cacheKey
+func (sch *Scholar) queryListing(ctx context.Context, params url.Values, limit int, cacheKey string) ([]*Article, error) {
+	listing, listingOk := sch.cachedListing(cacheKey)
+	if listingOk {
+		fresh := !sch.expired(sch.cacheTTL.Search, cacheKey, listing.LastRetrieved)
+		if fresh && (listing.Complete || len(listing.Articles) >= limit) {
+			sch.logger.Debug("Cache hit for results", "key", cacheKey)
+			return limitArticles(listing.Articles, limit), nil
+		}
+	}
+	sch.logger.Debug("Cache miss for results", "key", cacheKey)
+
+	listing = Listing{Key: cacheKey}
+	for start := 0; len(listing.Articles) < limit; start += searchPageSize {
+		pageArticles, err := sch.fetchResultsPage(ctx, params, start)
+		if err != nil {
+			return nil, err
+		}
+		listing.Articles = append(listing.Articles, pageArticles...)
+
+		// If we got fewer results than a full page, we've reached the end
+		if len(pageArticles) < searchPageSize {
+			listing.Complete = true
+			break
+		}
+	}
+	listing.LastRetrieved = sch.clock.Now()
+	sch.cacheListing(listing)
+	return limitArticles(listing.Articles, limit), nil
+}
+
+// limitArticles returns at most limit articles
+func limitArticles(articles []*Article, limit int) []*Article {
+	if len(articles) > limit {
+		return articles[:limit]
+	}
+	return articles
+}
+
+// fetchResultsPage fetches a single page of results starting from result number start
+func (sch *Scholar) fetchResultsPage(ctx context.Context, params url.Values, start int) ([]*Article, error) {
+	pageParams := url.Values{}
+	for key, value := range params {
+		pageParams[key] = value
+	}
+	if start > 0 {
+		pageParams.Set("start", strconv.Itoa(start))
+	}
+
+	requestURL := sch.baseURL + "/scholar?" + pageParams.Encode()
+	doc, err := sch.fetchDocument(ctx, requestURL, false)
+	if err != nil {
+		return nil, err
+	}
+	return parseResults(doc, sch.baseURL, sch.clock.Now()), nil
+}
+
+// parseResults parses the results (.gs_r.gs_or) of a results page retrieved at retrieved
+func parseResults(doc *goquery.Document, baseURL string, retrieved time.Time) []*Article {
+	var articles []*Article
+	doc.Find(".gs_r.gs_or").Each(func(i int, s *goquery.Selection) {
+		article := &Article{LastRetrieved: retrieved}
+		title := s.Find(".gs_rt")
+		title.Find(".gs_ctc, .gs_ctu, .gs_ctg2").Remove() // [PDF], [BOOK], [CITATION] etc. tags
+		article.Title = normalizeSpace(title.Text())
+		article.URL, _ = title.Find("a").Attr("href")
+		article.PdfURL, _ = s.Find(".gs_or_ggsm a").First().Attr("href")
+		article.Description = normalizeSpace(s.Find(".gs_rs").Text())
+		parseAuthorsLine(article, s.Find(".gs_a").Text())
+
+		s.Find(".gs_fl a").Each(func(i int, l *goquery.Selection) {
+			linkText := normalizeSpace(l.Text())
+			href, _ := l.Attr("href")
+			if href == "" || strings.HasPrefix(href, "javascript:") {
+				return
+			}
+			linkURL := href
+			if strings.HasPrefix(href, "/") {
+				linkURL = baseURL + href
+			}
+			parsed, err := url.Parse(linkURL)
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(linkText, "Cited by"):
+				article.NumCitations = parseLeadingInt(strings.TrimPrefix(linkText, "Cited by"))
+				article.ScholarCitedByURLs = append(article.ScholarCitedByURLs, linkURL)
+				if cites := parsed.Query().Get("cites"); cites != "" {
+					article.ClusterID = cites
+				}
+			case strings.HasPrefix(linkText, "Related articles"):
+				article.ScholarRelatedURLs = append(article.ScholarRelatedURLs, linkURL)
+			case strings.HasSuffix(linkText, "versions"):
+				article.ScholarVersionsURLs = append(article.ScholarVersionsURLs, linkURL)
+				if cluster := parsed.Query().Get("cluster"); cluster != "" && article.ClusterID == "" {
+					article.ClusterID = cluster
+				}
+			}
+		})
+		articles = append(articles, article)
+	})
+	return articles
+}
+
+// parseAuthorsLine parses the line below the title of a result, which has the form
+// "authors - venue, year - host", where the venue or the year may be missing
+func parseAuthorsLine(article *Article, line string) {
+	parts := strings.Split(normalizeSpace(line), " - ")
+	article.Authors = parts[0]
+	if len(parts) < 2 {
+		return
+	}
+	venue := parts[1]
+	if comma := strings.LastIndex(venue, ", "); comma >= 0 {
+		if year, err := strconv.Atoi(venue[comma+2:]); err == nil {
+			article.Year = year
+			venue = venue[:comma]
+		}
+	} else if year, err := strconv.Atoi(venue); err == nil {
+		article.Year = year
+		venue = ""
+	}
+	article.Journal = venue
+}
+
+// normalizeSpace collapses all runs of whitespace (including non-breaking spaces) into single spaces
+func normalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
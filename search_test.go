@@ -0,0 +1,152 @@
+package go_scholar
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// MockCountingHTTPClient counts the requests made through MockHTTPClient
+type MockCountingHTTPClient struct {
+	MockHTTPClient
+	callCount int
+}
+
+func (m *MockCountingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.callCount++
+	return m.MockHTTPClient.Do(req)
+}
+
+func TestSearch(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+
+	articles, err := sch.Search(context.Background(), SearchQuery{Query: "blockchain"})
+	assert.NoError(t, err)
+	assert.Len(t, articles, 3)
+
+	article := articles[0]
+	assert.Equal(t, "Decentralized applications: The blockchain-empowered software system", article.Title)
+	assert.Equal(t, "W Cai, Z Wang, JB Ernst, Z Hong, C Feng…", article.Authors)
+	assert.Equal(t, "IEEE access", article.Journal)
+	assert.Equal(t, 2018, article.Year)
+	assert.Equal(t, 485, article.NumCitations)
+	assert.Equal(t, "16589742970128240413", article.ClusterID)
+	assert.Equal(t, "https://ieeexplore.ieee.org/abstract/document/8466786/", article.URL)
+	assert.Equal(t, "https://ieeexplore.ieee.org/iel7/6287639/8274985/08466786.pdf", article.PdfURL)
+	assert.Contains(t, article.Description, "Blockchain technology has attracted tremendous attention")
+	assert.Equal(t, []string{BaseURL + "/scholar?cites=16589742970128240413&as_sdt=5,33&sciodt=0,33&hl=en"}, article.ScholarCitedByURLs)
+	assert.Len(t, article.ScholarRelatedURLs, 1)
+	assert.Equal(t, []string{BaseURL + "/scholar?cluster=16589742970128240413&hl=en&as_sdt=0,33"}, article.ScholarVersionsURLs)
+
+	// the [BOOK] tag is stripped from the title and a venue without a year is handled
+	assert.Equal(t, "Cognitive radio networks for heterogeneous wireless networks", articles[1].Title)
+	assert.Equal(t, 2013, articles[1].Year)
+	assert.Equal(t, "", articles[1].Journal)
+
+	// citations have no link and no cited by count
+	assert.Equal(t, "Hybrid wireless networks for emergency communications", articles[2].Title)
+	assert.Equal(t, "", articles[2].URL)
+	assert.Equal(t, 0, articles[2].NumCitations)
+}
+
+func TestSearchCache(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	mockClient := &MockCountingHTTPClient{}
+	sch.SetHTTPClient(mockClient)
+
+	_, err := sch.Search(context.Background(), SearchQuery{Query: "blockchain", Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mockClient.callCount)
+
+	// the listing is complete, so a larger limit is still served from the cache
+	articles, err := sch.Search(context.Background(), SearchQuery{Query: "blockchain", Limit: 20})
+	assert.NoError(t, err)
+	assert.Len(t, articles, 3)
+	assert.Equal(t, 1, mockClient.callCount)
+
+	_, err = sch.Search(context.Background(), SearchQuery{Query: "wireless"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mockClient.callCount)
+}
+
+func TestSearchEmptyQuery(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetHTTPClient(&MockAlwaysFailHTTPClient{})
+
+	_, err := sch.Search(context.Background(), SearchQuery{Query: " "})
+	assert.Error(t, err)
+}
+
+// MockRecordingHTTPClient records the URLs requested through MockHTTPClient
+type MockRecordingHTTPClient struct {
+	MockHTTPClient
+	urls []string
+}
+
+func (m *MockRecordingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.urls = append(m.urls, req.URL.String())
+	return m.MockHTTPClient.Do(req)
+}
+
+func TestSearchFilters(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetRequestDelay(1 * time.Millisecond)
+	mockClient := &MockRecordingHTTPClient{}
+	sch.SetHTTPClient(mockClient)
+
+	query := SearchQuery{
+		Query:          "wireless",
+		ExactPhrase:    "mesh networks",
+		ExcludeWords:   "satellite",
+		Author:         "JB Ernst",
+		Source:         "IEEE access",
+		YearLow:        2014,
+		YearHigh:       2018,
+		ExcludePatents: true,
+	}
+	_, err := sch.Search(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, mockClient.urls, 1)
+	requestURL, err := url.Parse(mockClient.urls[0])
+	assert.NoError(t, err)
+	params := requestURL.Query()
+	assert.Equal(t, "wireless", params.Get("q"))
+	assert.Equal(t, "mesh networks", params.Get("as_epq"))
+	assert.Equal(t, "satellite", params.Get("as_eq"))
+	assert.Equal(t, "JB Ernst", params.Get("as_sauthors"))
+	assert.Equal(t, "IEEE access", params.Get("as_publication"))
+	assert.Equal(t, "2014", params.Get("as_ylo"))
+	assert.Equal(t, "2018", params.Get("as_yhi"))
+	assert.Equal(t, "1,5", params.Get("as_sdt"))
+
+	// the same query is served from the cache, a differently filtered one is not
+	_, err = sch.Search(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, mockClient.urls, 1)
+
+	query.YearHigh = 2020
+	_, err = sch.Search(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, mockClient.urls, 2)
+
+	query.CaseLaw = true
+	_, err = sch.Search(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, mockClient.urls, 3)
+	requestURL, _ = url.Parse(mockClient.urls[2])
+	assert.Equal(t, "2006", requestURL.Query().Get("as_sdt"))
+}
+
+func TestSearchInvalidYearRange(t *testing.T) {
+	sch := New("profiles.json", "articles.json")
+	sch.SetHTTPClient(&MockAlwaysFailHTTPClient{})
+
+	_, err := sch.Search(context.Background(), SearchQuery{Query: "wireless", YearLow: 2020, YearHigh: 2010})
+	assert.Error(t, err)
+}
@@ -0,0 +1,242 @@
+package go_scholar
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Store is the storage of the profile, article and listing caches. Profiles are keyed by their User, articles
+// by their ScholarURL and listings by their Key. The links to Google Scholar of the stored values, including
+// the ScholarURL keys, are relative to the host (see relativeScholarURL). The LastRetrieved timestamps of the
+// stored values are used to expire them, so a Store must keep them as they are
+type Store interface {
+	LoadProfile(user string) (Profile, bool, error)
+	StoreProfile(profile Profile) error
+	DeleteProfile(user string) error
+	ListProfiles() ([]CacheEntry, error)
+
+	LoadArticle(articleURL string) (*Article, bool, error)
+	StoreArticle(article *Article) error
+	DeleteArticle(articleURL string) error
+	ListArticles() ([]CacheEntry, error)
+
+	LoadListing(key string) (Listing, bool, error)
+	StoreListing(listing Listing) error
+	DeleteListing(key string) error
+	ListListings() ([]CacheEntry, error)
+}
+
+// CacheEntry is a profile, article or listing in a Store along with when it was retrieved from Google Scholar
+type CacheEntry struct {
+	Key           string // User of a profile, ScholarURL of an article, Key of a listing
+	LastRetrieved time.Time
+}
+
+// MemoryStore is a Store which keeps everything in memory
+type MemoryStore struct {
+	articles sync.Map // map of articles by URL
+	profile  sync.Map // map of profile by User string
+	listings sync.Map // map of listings by Key
+	deleted  sync.Map // LastRetrieved of the deleted values by deletedKey, see deletedVersion
+}
+
+// deletedKey is the key of a deleted value, kind is "profiles", "articles" or "listings"
+type deletedKey struct {
+	kind string
+	key  string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) LoadProfile(user string) (Profile, bool, error) {
+	profileResult, profileOk := m.profile.Load(user)
+	if !profileOk {
+		return Profile{}, false, nil
+	}
+	return profileResult.(Profile), true, nil
+}
+
+func (m *MemoryStore) StoreProfile(profile Profile) error {
+	m.profile.Store(profile.User, profile)
+	m.deleted.Delete(deletedKey{"profiles", profile.User})
+	return nil
+}
+
+func (m *MemoryStore) DeleteProfile(user string) error {
+	if profileResult, profileOk := m.profile.LoadAndDelete(user); profileOk {
+		m.deleted.Store(deletedKey{"profiles", user}, profileResult.(Profile).LastRetrieved)
+	}
+	return nil
+}
+
+func (m *MemoryStore) ListProfiles() ([]CacheEntry, error) {
+	var entries []CacheEntry
+	m.profile.Range(func(key, value interface{}) bool {
+		entries = append(entries, CacheEntry{Key: key.(string), LastRetrieved: value.(Profile).LastRetrieved})
+		return true
+	})
+	return entries, nil
+}
+
+func (m *MemoryStore) LoadArticle(articleURL string) (*Article, bool, error) {
+	articleResult, articleOk := m.articles.Load(articleURL)
+	if !articleOk {
+		return nil, false, nil
+	}
+	return articleResult.(*Article), true, nil
+}
+
+func (m *MemoryStore) StoreArticle(article *Article) error {
+	m.articles.Store(article.ScholarURL, article)
+	m.deleted.Delete(deletedKey{"articles", article.ScholarURL})
+	return nil
+}
+
+func (m *MemoryStore) DeleteArticle(articleURL string) error {
+	if articleResult, articleOk := m.articles.LoadAndDelete(articleURL); articleOk {
+		m.deleted.Store(deletedKey{"articles", articleURL}, articleResult.(*Article).LastRetrieved)
+	}
+	return nil
+}
+
+func (m *MemoryStore) ListArticles() ([]CacheEntry, error) {
+	var entries []CacheEntry
+	m.articles.Range(func(key, value interface{}) bool {
+		entries = append(entries, CacheEntry{Key: key.(string), LastRetrieved: value.(*Article).LastRetrieved})
+		return true
+	})
+	return entries, nil
+}
+
+func (m *MemoryStore) LoadListing(key string) (Listing, bool, error) {
+	listingResult, listingOk := m.listings.Load(key)
+	if !listingOk {
+		return Listing{}, false, nil
+	}
+	return listingResult.(Listing), true, nil
+}
+
+func (m *MemoryStore) StoreListing(listing Listing) error {
+	m.listings.Store(listing.Key, listing)
+	m.deleted.Delete(deletedKey{"listings", listing.Key})
+	return nil
+}
+
+func (m *MemoryStore) DeleteListing(key string) error {
+	if listingResult, listingOk := m.listings.LoadAndDelete(key); listingOk {
+		m.deleted.Store(deletedKey{"listings", key}, listingResult.(Listing).LastRetrieved)
+	}
+	return nil
+}
+
+func (m *MemoryStore) ListListings() ([]CacheEntry, error) {
+	var entries []CacheEntry
+	m.listings.Range(func(key, value interface{}) bool {
+		entries = append(entries, CacheEntry{Key: key.(string), LastRetrieved: value.(Listing).LastRetrieved})
+		return true
+	})
+	return entries, nil
+}
+
+// deletedVersion returns the LastRetrieved of the value of kind deleted with key, and false if no value with
+// key has been deleted since one was last stored. Saving to the cache files uses it to leave the deleted
+// values out, rather than merge them back from the files
+func (m *MemoryStore) deletedVersion(kind string, key string) (time.Time, bool) {
+	lastRetrieved, ok := m.deleted.Load(deletedKey{kind, key})
+	if !ok {
+		return time.Time{}, false
+	}
+	return lastRetrieved.(time.Time), true
+}
+
+// JSONFileStore is a MemoryStore which is loaded from JSON files, a map of profiles by User, a map of articles
+// by ScholarURL and a map of listings by Key, and written back to them by Save
+type JSONFileStore struct {
+	*MemoryStore
+	ProfilePath string
+	ArticlePath string
+	ListingPath string       // next to ArticlePath by default, see listingCachePath
+	Logger      *slog.Logger // logger of Save, nil to discard its messages
+}
+
+// NewJSONFileStore returns a JSONFileStore loaded from the files at profilePath and articlePath, and the
+// listings file next to articlePath. If they can't be loaded, the error is returned along with an empty
+// store which still saves to those paths
+func NewJSONFileStore(profilePath string, articlePath string) (*JSONFileStore, error) {
+	store := &JSONFileStore{
+		MemoryStore: NewMemoryStore(),
+		ProfilePath: profilePath,
+		ArticlePath: articlePath,
+		ListingPath: listingCachePath(articlePath),
+	}
+	err := loadCacheFiles(store, profilePath, articlePath, store.ListingPath, discardLogger)
+	return store, err
+}
+
+// Save writes the store to its files
+func (j *JSONFileStore) Save() error {
+	return saveCacheFiles(j, j.ProfilePath, j.ArticlePath, j.ListingPath, orDiscard(j.Logger))
+}
+
+// cachedProfile loads a profile from the store, store errors are reported and treated as cache misses
+func (sch *Scholar) cachedProfile(user string) (Profile, bool) {
+	profile, ok, err := sch.store.LoadProfile(user)
+	if err != nil {
+		sch.logger.Error("Loading profile from cache failed", "user", user, "error", err)
+		return Profile{}, false
+	}
+	if !ok {
+		return Profile{}, false
+	}
+	return convertProfileURLs(profile, absoluteScholarURL, sch.baseURL), true
+}
+
+// cacheProfile stores a profile, store errors are reported since the profile can still be returned
+func (sch *Scholar) cacheProfile(profile Profile) {
+	if err := sch.store.StoreProfile(convertProfileURLs(profile, relativeScholarURL, sch.baseURL)); err != nil {
+		sch.logger.Error("Storing profile in cache failed", "user", profile.User, "error", err)
+	}
+}
+
+// cachedArticle loads an article from the store, store errors are reported and treated as cache misses
+func (sch *Scholar) cachedArticle(articleURL string) (*Article, bool) {
+	article, ok, err := sch.store.LoadArticle(relativeScholarURL(articleURL, sch.baseURL))
+	if err != nil {
+		sch.logger.Error("Loading article from cache failed", "url", articleURL, "error", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	return convertArticleURLs(article, absoluteScholarURL, sch.baseURL), true
+}
+
+// cacheArticle stores an article, store errors are reported since the article can still be returned
+func (sch *Scholar) cacheArticle(article *Article) {
+	if err := sch.store.StoreArticle(convertArticleURLs(article, relativeScholarURL, sch.baseURL)); err != nil {
+		sch.logger.Error("Storing article in cache failed", "url", article.ScholarURL, "error", err)
+	}
+}
+
+// cachedListing loads a listing from the store, store errors are reported and treated as cache misses
+func (sch *Scholar) cachedListing(key string) (Listing, bool) {
+	listing, ok, err := sch.store.LoadListing(key)
+	if err != nil {
+		sch.logger.Error("Loading listing from cache failed", "key", key, "error", err)
+		return Listing{}, false
+	}
+	if !ok {
+		return Listing{}, false
+	}
+	return convertListingURLs(listing, absoluteScholarURL, sch.baseURL), true
+}
+
+// cacheListing stores a listing, store errors are reported since the results can still be returned
+func (sch *Scholar) cacheListing(listing Listing) {
+	if err := sch.store.StoreListing(convertListingURLs(listing, relativeScholarURL, sch.baseURL)); err != nil {
+		sch.logger.Error("Storing listing in cache failed", "key", listing.Key, "error", err)
+	}
+}
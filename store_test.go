@@ -0,0 +1,100 @@
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// Test the get/put/delete/list operations of the memory store
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	retrieved := time.Now().Add(-time.Hour)
+
+	_, ok, err := store.LoadProfile("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.StoreProfile(Profile{User: "SbUmSEAAAAAJ", Name: "Jason Ernst", LastRetrieved: retrieved}))
+	profile, ok, err := store.LoadProfile("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Jason Ernst", profile.Name)
+
+	assert.NoError(t, store.StoreArticle(&Article{ScholarURL: sampleArticleURL, Title: "Title", LastRetrieved: retrieved}))
+	assert.NoError(t, store.StoreArticle(&Article{ScholarURL: sampleArticleURL + "2", LastRetrieved: retrieved}))
+	article, ok, err := store.LoadArticle(sampleArticleURL)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Title", article.Title)
+
+	profiles, err := store.ListProfiles()
+	assert.NoError(t, err)
+	assert.Equal(t, []CacheEntry{{Key: "SbUmSEAAAAAJ", LastRetrieved: retrieved}}, profiles)
+	articles, err := store.ListArticles()
+	assert.NoError(t, err)
+	assert.Len(t, articles, 2)
+
+	assert.NoError(t, store.DeleteProfile("SbUmSEAAAAAJ"))
+	assert.NoError(t, store.DeleteArticle(sampleArticleURL))
+	_, ok, _ = store.LoadProfile("SbUmSEAAAAAJ")
+	assert.False(t, ok)
+	_, ok, _ = store.LoadArticle(sampleArticleURL)
+	assert.False(t, ok)
+	articles, _ = store.ListArticles()
+	assert.Equal(t, []CacheEntry{{Key: sampleArticleURL + "2", LastRetrieved: retrieved}}, articles)
+
+	assert.NoError(t, store.StoreListing(Listing{Key: "cites:123", Articles: []*Article{{Title: "Title"}}, LastRetrieved: retrieved}))
+	listing, ok, err := store.LoadListing("cites:123")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Title", listing.Articles[0].Title)
+	listings, err := store.ListListings()
+	assert.NoError(t, err)
+	assert.Equal(t, []CacheEntry{{Key: "cites:123", LastRetrieved: retrieved}}, listings)
+	assert.NoError(t, store.DeleteListing("cites:123"))
+	_, ok, _ = store.LoadListing("cites:123")
+	assert.False(t, ok)
+}
+
+// Test that the JSON file store starts empty without its files and reads back what it saved
+func TestJSONFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewJSONFileStore(dir+"/profiles.json", dir+"/articles.json")
+	assert.Error(t, err)
+	assert.NotNil(t, store)
+
+	sch := NewWithStore(store)
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockHTTPClient{})
+	_, err = sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 2)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save())
+
+	loaded, err := NewJSONFileStore(dir+"/profiles.json", dir+"/articles.json")
+	assert.NoError(t, err)
+	profile, ok, err := loaded.LoadProfile("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, profile.Articles, 2)
+
+	for _, articleURL := range profile.Articles {
+		article, ok, err := loaded.LoadArticle(articleURL)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, articleURL, article.ScholarURL)
+	}
+}
+
+// Test that the queries use the store passed to NewWithStore
+func TestNewWithStore(t *testing.T) {
+	store := NewMemoryStore()
+	store.StoreProfile(Profile{User: "SbUmSEAAAAAJ", Name: "Cached Name", LastRetrieved: time.Now()})
+
+	sch := NewWithStore(store)
+	sch.SetRequestDelay(1 * time.Millisecond)
+	sch.SetHTTPClient(&MockAlwaysFailHTTPClient{})
+	profile, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cached Name", profile.Name)
+}
@@ -0,0 +1,84 @@
+package go_scholar
+
+import (
+	"errors"
+	"time"
+)
+
+// AdaptiveThrottle adapts the delay between requests to how Google Scholar responds. The delay is widened
+// multiplicatively when Google Scholar pushes back (a 429 or a CAPTCHA or unusual traffic page), once per
+// request however many times it is retried, and narrowed additively after a run of successful requests, always
+// staying within MinDelay and MaxDelay
+type AdaptiveThrottle struct {
+	MinDelay  time.Duration // shortest delay between requests
+	MaxDelay  time.Duration // longest delay between requests
+	Factor    float64       // multiplier of the delay after pushback, more than 1
+	Step      time.Duration // the delay is narrowed by Step after each run of Successes successful requests
+	Successes int           // number of successful requests in a row before the delay is narrowed
+}
+
+// DefaultAdaptiveThrottle doubles the delay after pushback, up to 2 minutes, and narrows it by 250ms after
+// every 10 successful requests, down to the default delay of 2 seconds
+var DefaultAdaptiveThrottle = AdaptiveThrottle{
+	MinDelay:  2 * time.Second,
+	MaxDelay:  2 * time.Minute,
+	Factor:    2,
+	Step:      250 * time.Millisecond,
+	Successes: 10,
+}
+
+// validate returns an error if the settings of the throttle can't be used
+func (a AdaptiveThrottle) validate() error {
+	switch {
+	case a.MinDelay < 0 || a.MaxDelay <= 0 || a.MinDelay > a.MaxDelay:
+		return errors.New("Scholar: adaptive throttle delays must satisfy 0 <= MinDelay <= MaxDelay, 0 < MaxDelay")
+	case a.Factor <= 1:
+		return errors.New("Scholar: adaptive throttle factor must be more than 1")
+	case a.Step <= 0:
+		return errors.New("Scholar: adaptive throttle step must be positive")
+	case a.Successes < 1:
+		return errors.New("Scholar: adaptive throttle successes must be at least 1")
+	}
+	return nil
+}
+
+// clamp returns delay within the bounds of the throttle
+func (a AdaptiveThrottle) clamp(delay time.Duration) time.Duration {
+	return min(max(delay, a.MinDelay), a.MaxDelay)
+}
+
+// CurrentDelay returns the delay currently applied between requests. It only changes over time with
+// WithAdaptiveThrottle, and can be monitored to see how hard Google Scholar is pushing back
+func (sch *Scholar) CurrentDelay() time.Duration {
+	sch.requestMutex.Lock()
+	defer sch.requestMutex.Unlock()
+	return sch.requestDelay
+}
+
+// adaptDelay widens the delay between requests after pushback, or counts a successful request towards
+// narrowing it, if the Scholar has an adaptive throttle
+func (sch *Scholar) adaptDelay(pushback bool) {
+	if sch.throttle == nil {
+		return
+	}
+	sch.requestMutex.Lock()
+	defer sch.requestMutex.Unlock()
+	throttle := sch.throttle
+	previous := sch.requestDelay
+	if pushback {
+		sch.successes = 0
+		// at least one step longer, so that a delay of 0 is widened too
+		widened := max(time.Duration(float64(previous)*throttle.Factor), previous+throttle.Step)
+		sch.requestDelay = throttle.clamp(widened)
+		sch.logger.Info("Widened request delay after pushback", "delay", sch.requestDelay, "previous_delay", previous)
+		return
+	}
+	sch.successes++
+	if sch.successes >= throttle.Successes {
+		sch.successes = 0
+		sch.requestDelay = throttle.clamp(previous - throttle.Step)
+		if sch.requestDelay != previous {
+			sch.logger.Debug("Narrowed request delay after successful requests", "delay", sch.requestDelay, "previous_delay", previous)
+		}
+	}
+}
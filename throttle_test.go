@@ -0,0 +1,136 @@
+package go_scholar
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testThrottle = AdaptiveThrottle{
+	MinDelay:  time.Second,
+	MaxDelay:  5 * time.Second,
+	Factor:    2,
+	Step:      500 * time.Millisecond,
+	Successes: 2,
+}
+
+// Test that the delay is widened after 429s up to the max delay, and narrowed after successful requests
+func TestAdaptiveThrottle(t *testing.T) {
+	mockClient := &MockSequenceHTTPClient{responses: []*http.Response{
+		mockHeaderResponse(429),
+		mockHeaderResponse(429),
+		mockHeaderResponse(429),
+	}}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithClock(&MockClock{}), WithRequestDelay(time.Second),
+		WithAdaptiveThrottle(testThrottle))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, sch.CurrentDelay())
+
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, sch.CurrentDelay(), "widened once for the request, however many times it was retried")
+
+	_, err = sch.QueryArticle(sampleArticleURL, &Article{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, sch.CurrentDelay())
+
+	for i := 0; i < 20; i++ {
+		_, err = sch.QueryArticle(sampleArticleURL, &Article{}, false)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, time.Second, sch.CurrentDelay(), "narrowed down to the min delay")
+}
+
+// Test that each rate limited request widens the delay once, whether its retries succeed or run out
+func TestAdaptiveThrottleOncePerRequest(t *testing.T) {
+	var responses []*http.Response
+	for i := 0; i < 2*(DefaultRetryPolicy.MaxRetries+1)+3; i++ {
+		responses = append(responses, mockHeaderResponse(429))
+	}
+	var output bytes.Buffer
+	sch, err := NewWithOptions(WithHTTPClient(&MockSequenceHTTPClient{responses: responses}), WithClock(&MockClock{}),
+		WithRequestDelay(time.Second), WithAdaptiveThrottle(testThrottle),
+		WithLogger(slog.New(slog.NewJSONHandler(&output, nil))))
+	assert.NoError(t, err)
+
+	widenings := func() int {
+		count := 0
+		decoder := json.NewDecoder(bytes.NewReader(output.Bytes()))
+		for decoder.More() {
+			var record map[string]interface{}
+			assert.NoError(t, decoder.Decode(&record))
+			if record["msg"] == "Widened request delay after pushback" {
+				count++
+			}
+		}
+		return count
+	}
+
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 1, widenings())
+	assert.Equal(t, 2*time.Second, sch.CurrentDelay())
+
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 2, widenings())
+	assert.Equal(t, 4*time.Second, sch.CurrentDelay())
+
+	// the last request succeeds after 3 retries
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, widenings())
+	assert.Equal(t, 5*time.Second, sch.CurrentDelay(), "capped at the max delay")
+}
+
+// Test that CAPTCHA and unusual traffic pages widen the delay, but consent pages don't
+func TestAdaptiveThrottleBlockPages(t *testing.T) {
+	testCases := map[string]struct {
+		client HTTPClient
+		delay  time.Duration
+	}{
+		"captcha":         {&MockStatusHTTPClient{statusCode: 200, body: sampleCaptchaPage}, 2 * time.Second},
+		"unusual traffic": {&MockRedirectHTTPClient{location: "https://www.google.com/sorry/index"}, 2 * time.Second},
+		"consent":         {&MockRedirectHTTPClient{location: "https://consent.google.com/ml"}, time.Second},
+	}
+	for reason, testCase := range testCases {
+		t.Run(reason, func(t *testing.T) {
+			sch, err := NewWithOptions(WithHTTPClient(testCase.client), WithClock(&MockClock{}),
+				WithAdaptiveThrottle(testThrottle), WithRequestDelay(0))
+			assert.NoError(t, err)
+			assert.Equal(t, time.Second, sch.CurrentDelay(), "the request delay is brought within the bounds")
+			_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+			assert.ErrorIs(t, err, ErrBlocked)
+			assert.Equal(t, testCase.delay, sch.CurrentDelay())
+		})
+	}
+}
+
+func TestWithAdaptiveThrottle(t *testing.T) {
+	for _, throttle := range []AdaptiveThrottle{
+		{MinDelay: -time.Second, MaxDelay: time.Second, Factor: 2, Step: time.Second, Successes: 1},
+		{MinDelay: 2 * time.Second, MaxDelay: time.Second, Factor: 2, Step: time.Second, Successes: 1},
+		{MinDelay: time.Second, MaxDelay: 2 * time.Second, Factor: 1, Step: time.Second, Successes: 1},
+		{MinDelay: time.Second, MaxDelay: 2 * time.Second, Factor: 2, Step: 0, Successes: 1},
+		{MinDelay: time.Second, MaxDelay: 2 * time.Second, Factor: 2, Step: time.Second, Successes: 0},
+	} {
+		_, err := NewWithOptions(WithAdaptiveThrottle(throttle))
+		assert.Error(t, err, "%+v", throttle)
+	}
+
+	// without an adaptive throttle the delay is fixed
+	sch, err := NewWithOptions(WithHTTPClient(&MockStatusHTTPClient{statusCode: 200, body: sampleCaptchaPage}),
+		WithClock(&MockClock{}), WithRequestDelay(time.Second))
+	assert.NoError(t, err)
+	_, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.ErrorIs(t, err, ErrBlocked)
+	assert.Equal(t, time.Second, sch.CurrentDelay())
+
+	sch, err = NewWithOptions(WithAdaptiveThrottle(DefaultAdaptiveThrottle))
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, sch.CurrentDelay())
+}
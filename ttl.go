@@ -0,0 +1,71 @@
+package go_scholar
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// TTL values with a special meaning in CacheTTL
+const (
+	NeverExpire   time.Duration = -1 // cached entries are always served once cached
+	AlwaysRefresh time.Duration = -2 // cached entries are only served when a refresh fails
+)
+
+// CacheTTL is how long cached profiles, articles and search results are served before they are refreshed
+// from Google Scholar. Each TTL may also be NeverExpire or AlwaysRefresh, and a TTL which is left out (zero)
+// is the one of DefaultCacheTTL.
+//
+// Jitter spreads the expiry of entries retrieved at the same time, so that the thousands of articles cached
+// by one crawl don't all expire on the same day. Each entry's TTL is lengthened or shortened by up to this
+// fraction (e.g. 0.1 for ±10%), by an amount derived from its key so it doesn't change between lookups
+type CacheTTL struct {
+	Profile time.Duration
+	Article time.Duration
+	Search  time.Duration
+	Jitter  float64
+}
+
+// DefaultCacheTTL is the CacheTTL of a new Scholar
+var DefaultCacheTTL = CacheTTL{
+	Profile: MAX_TIME_PROFILE,
+	Article: MAX_TIME_ARTICLE,
+	Search:  MAX_TIME_SEARCH,
+}
+
+// withDefaults returns ttl with the TTLs which are left out set to those of DefaultCacheTTL
+func (ttl CacheTTL) withDefaults() CacheTTL {
+	if ttl.Profile == 0 {
+		ttl.Profile = DefaultCacheTTL.Profile
+	}
+	if ttl.Article == 0 {
+		ttl.Article = DefaultCacheTTL.Article
+	}
+	if ttl.Search == 0 {
+		ttl.Search = DefaultCacheTTL.Search
+	}
+	return ttl
+}
+
+// expired returns whether an entry with the given key retrieved at lastRetrieved is older than ttl (with
+// the jitter applied)
+func (sch *Scholar) expired(ttl time.Duration, key string, lastRetrieved time.Time) bool {
+	if ttl == NeverExpire {
+		return false
+	}
+	if ttl == AlwaysRefresh {
+		return true
+	}
+	return sch.clock.Now().Sub(lastRetrieved) > jitterTTL(ttl, sch.cacheTTL.Jitter, key)
+}
+
+// jitterTTL lengthens or shortens ttl by up to the fraction jitter, by an amount derived from key
+func jitterTTL(ttl time.Duration, jitter float64, key string) time.Duration {
+	if jitter <= 0 {
+		return ttl
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	// map the hash onto [-1, 1)
+	offset := float64(hash.Sum64()>>11)/float64(1<<53)*2 - 1
+	return ttl + time.Duration(offset*jitter*float64(ttl))
+}
@@ -0,0 +1,98 @@
+package go_scholar
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// Test that NeverExpire serves old entries and AlwaysRefresh refreshes fresh ones
+func TestCacheTTL(t *testing.T) {
+	store := NewMemoryStore()
+	store.StoreProfile(Profile{User: "SbUmSEAAAAAJ", Name: "Cached Name", LastRetrieved: time.Now().Add(-365 * 24 * time.Hour)})
+	mockClient := &MockCountingHTTPClient{}
+	sch, err := NewWithOptions(WithStore(store), WithHTTPClient(mockClient), WithRequestDelay(time.Millisecond),
+		WithCacheTTL(CacheTTL{Profile: NeverExpire, Article: NeverExpire, Search: NeverExpire}))
+	assert.NoError(t, err)
+
+	profile, err := sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cached Name", profile.Name)
+	assert.Equal(t, 0, mockClient.callCount)
+
+	sch, err = NewWithOptions(WithStore(store), WithHTTPClient(mockClient), WithRequestDelay(time.Millisecond),
+		WithCacheTTL(CacheTTL{Profile: AlwaysRefresh, Article: AlwaysRefresh, Search: AlwaysRefresh}))
+	assert.NoError(t, err)
+	profile, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Jason Ernst", profile.Name)
+	assert.Equal(t, 1, mockClient.callCount)
+	profile, err = sch.QueryProfileInfo("SbUmSEAAAAAJ")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mockClient.callCount)
+
+	_, err = sch.Search(context.Background(), SearchQuery{Query: "blockchain"})
+	assert.NoError(t, err)
+	_, err = sch.Search(context.Background(), SearchQuery{Query: "blockchain"})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, mockClient.callCount)
+}
+
+// Test that cached articles of a profile are served until the article TTL expires
+func TestCacheTTLArticles(t *testing.T) {
+	store := NewMemoryStore()
+	mockClient := &MockCountingHTTPClient{}
+	sch, err := NewWithOptions(WithStore(store), WithHTTPClient(mockClient), WithRequestDelay(time.Millisecond))
+	assert.NoError(t, err)
+
+	_, err = sch.QueryProfile("SbUmSEAAAAAJ", 2)
+	assert.NoError(t, err)
+	firstCount := mockClient.callCount
+
+	// only the profile page is requested for articles which haven't expired
+	_, err = sch.QueryProfile("SbUmSEAAAAAJ", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, firstCount+1, mockClient.callCount)
+
+	// the profile and search TTLs left out keep their defaults
+	sch, err = NewWithOptions(WithStore(store), WithHTTPClient(mockClient), WithRequestDelay(time.Millisecond),
+		WithCacheTTL(CacheTTL{Article: AlwaysRefresh}))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCacheTTL.Profile, sch.cacheTTL.Profile)
+	assert.Equal(t, DefaultCacheTTL.Search, sch.cacheTTL.Search)
+	_, err = sch.QueryProfile("SbUmSEAAAAAJ", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*firstCount+1, mockClient.callCount)
+}
+
+// Test that the jitter is deterministic per key, within bounds, and spreads different keys
+func TestJitterTTL(t *testing.T) {
+	ttl := 30 * 24 * time.Hour
+	assert.Equal(t, ttl, jitterTTL(ttl, 0, "key"))
+	assert.Equal(t, jitterTTL(ttl, 0.1, "key"), jitterTTL(ttl, 0.1, "key"))
+
+	seen := map[time.Duration]bool{}
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		jittered := jitterTTL(ttl, 0.1, key)
+		assert.GreaterOrEqual(t, jittered, ttl-ttl/10)
+		assert.LessOrEqual(t, jittered, ttl+ttl/10)
+		seen[jittered] = true
+	}
+	assert.Greater(t, len(seen), 1)
+}
+
+// Test that a CacheTTL which only sets some of the TTLs doesn't turn off the caches of the others
+func TestCacheTTLDefaults(t *testing.T) {
+	mockClient := &MockCountingHTTPClient{}
+	sch, err := NewWithOptions(WithHTTPClient(mockClient), WithRequestDelay(time.Millisecond),
+		WithCacheTTL(CacheTTL{Profile: 24 * time.Hour}))
+	assert.NoError(t, err)
+	assert.Equal(t, CacheTTL{Profile: 24 * time.Hour, Article: DefaultCacheTTL.Article, Search: DefaultCacheTTL.Search}, sch.cacheTTL)
+
+	_, err = sch.Search(context.Background(), SearchQuery{Query: "blockchain"})
+	assert.NoError(t, err)
+	_, err = sch.Search(context.Background(), SearchQuery{Query: "blockchain"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, mockClient.callCount)
+}
@@ -0,0 +1,82 @@
+package go_scholar
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Links to Google Scholar are cached relative to the host (e.g. "/citations?view_op=view_citation&..."), and
+// made absolute with the base URL of the Scholar they are loaded by, so that a cache stays valid when the base
+// URL changes, e.g. to a regional Google Scholar host, a mirror or a local server in tests
+
+// relativeScholarURL returns the path and query of link if it is on a Google Scholar host (scholar.google.*)
+// or on the host of baseURL. Other links, such as publisher links, are returned as they are
+func relativeScholarURL(link string, baseURL string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		return link
+	}
+	isScholarHost := strings.HasPrefix(parsed.Hostname(), "scholar.google.")
+	if base, err := url.Parse(baseURL); err == nil && base.Host != "" && base.Host == parsed.Host {
+		isScholarHost = true
+	}
+	if !isScholarHost {
+		return link
+	}
+	relative := parsed.EscapedPath()
+	if parsed.RawQuery != "" {
+		relative += "?" + parsed.RawQuery
+	}
+	if parsed.Fragment != "" {
+		relative += "#" + parsed.EscapedFragment()
+	}
+	return relative
+}
+
+// absoluteScholarURL returns link on the host of baseURL if it is relative to the host
+func absoluteScholarURL(link string, baseURL string) string {
+	if strings.HasPrefix(link, "/") && !strings.HasPrefix(link, "//") {
+		return baseURL + link
+	}
+	return link
+}
+
+// mapURLs returns a copy of links with convert applied to each of them
+func mapURLs(links []string, convert func(string, string) string, baseURL string) []string {
+	if links == nil {
+		return nil
+	}
+	converted := make([]string, len(links))
+	for i, link := range links {
+		converted[i] = convert(link, baseURL)
+	}
+	return converted
+}
+
+// convertArticleURLs returns a copy of article with convert applied to its links to Google Scholar
+func convertArticleURLs(article *Article, convert func(string, string) string, baseURL string) *Article {
+	converted := *article
+	converted.ScholarURL = convert(article.ScholarURL, baseURL)
+	converted.ScholarCitedByURLs = mapURLs(article.ScholarCitedByURLs, convert, baseURL)
+	converted.ScholarVersionsURLs = mapURLs(article.ScholarVersionsURLs, convert, baseURL)
+	converted.ScholarRelatedURLs = mapURLs(article.ScholarRelatedURLs, convert, baseURL)
+	return &converted
+}
+
+// convertProfileURLs returns a copy of profile with convert applied to its links to Google Scholar
+func convertProfileURLs(profile Profile, convert func(string, string) string, baseURL string) Profile {
+	profile.PhotoURL = convert(profile.PhotoURL, baseURL)
+	profile.Articles = mapURLs(profile.Articles, convert, baseURL)
+	return profile
+}
+
+// convertListingURLs returns a copy of listing with convert applied to the links to Google Scholar of its
+// articles
+func convertListingURLs(listing Listing, convert func(string, string) string, baseURL string) Listing {
+	articles := make([]*Article, len(listing.Articles))
+	for i, article := range listing.Articles {
+		articles[i] = convertArticleURLs(article, convert, baseURL)
+	}
+	listing.Articles = articles
+	return listing
+}
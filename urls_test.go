@@ -0,0 +1,61 @@
+package go_scholar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRelativeScholarURL(t *testing.T) {
+	assert.Equal(t, "/citations?user=SbUmSEAAAAAJ&hl=en", relativeScholarURL("https://scholar.google.com/citations?user=SbUmSEAAAAAJ&hl=en", ""))
+	assert.Equal(t, "/scholar?cites=123", relativeScholarURL("https://scholar.google.co.uk/scholar?cites=123", ""))
+	assert.Equal(t, "/scholar?cites=123", relativeScholarURL("http://localhost:8080/scholar?cites=123", "http://localhost:8080"))
+	assert.Equal(t, "http://localhost:8080/scholar?cites=123", relativeScholarURL("http://localhost:8080/scholar?cites=123", ""))
+	assert.Equal(t, "https://ieeexplore.ieee.org/document/1", relativeScholarURL("https://ieeexplore.ieee.org/document/1", BaseURL))
+	assert.Equal(t, "/citations?user=SbUmSEAAAAAJ", relativeScholarURL("/citations?user=SbUmSEAAAAAJ", BaseURL))
+
+	assert.Equal(t, "https://scholar.google.de/scholar?cites=123", absoluteScholarURL("/scholar?cites=123", "https://scholar.google.de"))
+	assert.Equal(t, "https://ieeexplore.ieee.org/document/1", absoluteScholarURL("https://ieeexplore.ieee.org/document/1", BaseURL))
+}
+
+// newMockServer returns a local server serving the sample pages of MockHTTPClient
+func newMockServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := (&MockHTTPClient{}).Do(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+}
+
+// Test that the library can be pointed at a local server, and that its cache stays valid for another host
+func TestBaseURLLocalServer(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+	store := NewMemoryStore()
+
+	sch, err := NewWithOptions(WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithStore(store), WithRequestDelay(time.Millisecond))
+	assert.NoError(t, err)
+	articles, err := sch.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 2)
+	assert.NoError(t, err)
+	assert.Len(t, articles, 2)
+	assert.True(t, strings.HasPrefix(articles[0].ScholarURL, server.URL+"/citations?"))
+	assert.NotEmpty(t, articles[0].Authors, "the article page was fetched from the server")
+
+	// the cache doesn't depend on the host it was filled from
+	other, err := NewWithOptions(WithBaseURL("https://scholar.google.co.uk"), WithHTTPClient(&MockAlwaysFailHTTPClient{}), WithStore(store), WithRequestDelay(time.Millisecond))
+	assert.NoError(t, err)
+	cached, err := other.QueryProfileWithMemoryCache("SbUmSEAAAAAJ", 2)
+	assert.NoError(t, err)
+	assert.Len(t, cached, 2)
+	assert.Equal(t, "https://scholar.google.co.uk"+strings.TrimPrefix(articles[0].ScholarURL, server.URL), cached[0].ScholarURL)
+	assert.Equal(t, articles[0].Title, cached[0].Title)
+}